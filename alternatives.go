@@ -0,0 +1,140 @@
+package docs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reOptionLabel matches the label written before each alternative.
+	reOptionLabel = regexp.MustCompile(`^\*\*Option: (.+?)(?: \(\d+\))?\*\*$`)
+)
+
+// codeLabel returns the display name of a code block language.
+func codeLabel(lang string) string {
+	switch lang = strings.ToLower(strings.TrimSpace(lang)); lang {
+	case "", "javascript", "js":
+		return "JavaScript"
+	case "typescript", "ts":
+		return "TypeScript"
+	case "bash", "sh", "shell":
+		return "Shell"
+	case "json", "yaml", "html", "sql":
+		return strings.ToUpper(lang)
+	case "yml":
+		return "YAML"
+	default:
+		return strings.ToUpper(lang[:1]) + lang[1:]
+	}
+}
+
+// alternativeLabels returns the "Option: <language>" labels of the fenced
+// blocks of a code shortcode, one per block language, or nil if there are
+// fewer than two blocks. The labels show readers that the blocks are
+// alternatives rather than consecutive steps. Labels that repeat, such as
+// two JavaScript tabs for different k6 versions, are numbered.
+func alternativeLabels(langs []string) []string {
+	if len(langs) < 2 {
+		return nil
+	}
+
+	labels := make([]string, len(langs))
+	count := make(map[string]int)
+	for i, lang := range langs {
+		labels[i] = codeLabel(lang)
+		count[labels[i]]++
+	}
+	seen := make(map[string]int)
+	for i, l := range labels {
+		if count[l] > 1 {
+			seen[l]++
+			labels[i] = fmt.Sprintf("%s (%d)", l, seen[l])
+		}
+	}
+	return labels
+}
+
+// alternative is a labelled code block: the label is at lines[line] and the
+// fenced block spans lines[start:end].
+type alternative struct {
+	label            string
+	line, start, end int
+}
+
+// parseAlternative returns the labelled code block starting at lines[i].
+func parseAlternative(lines []string, i int) (alternative, bool) {
+	m := reOptionLabel.FindStringSubmatch(lines[i])
+	if m == nil {
+		return alternative{}, false
+	}
+	j := i + 1
+	for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+		j++
+	}
+	if j == len(lines) {
+		return alternative{}, false
+	}
+	f, ok := openFence(lines[j])
+	if !ok {
+		return alternative{}, false
+	}
+	for k := j + 1; k < len(lines); k++ {
+		if f.closes(lines[k]) {
+			return alternative{label: m[1], line: i, start: j, end: k + 1}, true
+		}
+	}
+	return alternative{}, false
+}
+
+// PreferLang keeps only the alternative in lang, e.g. "ts" or "typescript",
+// wherever content shows a code example with labelled alternatives. Groups
+// without an alternative in lang are kept whole.
+func PreferLang(content, lang string) string {
+	if lang == "" {
+		return content
+	}
+	want := codeLabel(lang)
+
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	var f fence
+	for i := 0; i < len(lines); i++ {
+		var group []alternative
+		for next := i; !f.inside() && next < len(lines); {
+			alt, ok := parseAlternative(lines, next)
+			if !ok {
+				break
+			}
+			group = append(group, alt)
+			next = alt.end
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+		}
+
+		var kept []alternative
+		for _, alt := range group {
+			if alt.label == want {
+				kept = append(kept, alt)
+			}
+		}
+		if len(kept) == 0 {
+			f.step(lines[i])
+			out = append(out, lines[i])
+			continue
+		}
+
+		for n, alt := range kept {
+			if n > 0 {
+				out = append(out, "")
+			}
+			if len(kept) > 1 {
+				out = append(out, lines[alt.line], "")
+			}
+			out = append(out, lines[alt.start:alt.end]...)
+		}
+		i = group[len(group)-1].end - 1
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,155 @@
+package docs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+const tabbedExample = "Send a request:\n\n{{< code >}}\n\n" +
+	"```javascript\nhttp.get(url);\n```\n\n" +
+	"```typescript\nhttp.get(url as string);\n```\n\n" +
+	"{{< /code >}}\n\nThen check the response.\n"
+
+func TestTransform_CodeAlternatives(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "languages",
+			content: tabbedExample,
+			want: "Send a request:\n\n" +
+				"**Option: JavaScript**\n\n```javascript\nhttp.get(url);\n```\n\n" +
+				"**Option: TypeScript**\n\n```typescript\nhttp.get(url as string);\n```\n\n" +
+				"Then check the response.\n",
+		},
+		{
+			name:    "repeated_language",
+			content: "{{< code >}}\n```js\nv1();\n```\n```js\nv2();\n```\n{{< /code >}}",
+			want: "\n**Option: JavaScript (1)**\n\n```js\nv1();\n```\n\n" +
+				"**Option: JavaScript (2)**\n\n```js\nv2();\n```\n",
+		},
+		{
+			name:    "single_block",
+			content: "{{< code >}}\n```js\na();\n```\n{{< /code >}}",
+			want:    "\n```js\na();\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Transform(tt.content, "v1.0.0")
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferLang(t *testing.T) {
+	t.Parallel()
+
+	content := Transform(tabbedExample, "v1.0.0")
+	versions := Transform("{{< code >}}\n```js\nv1();\n```\n```js\nv2();\n```\n```ts\nv3();\n```\n{{< /code >}}", "v1.0.0")
+
+	tests := []struct {
+		name    string
+		content string
+		lang    string
+		want    string
+	}{
+		{
+			name:    "typescript",
+			content: content,
+			lang:    "ts",
+			want:    "Send a request:\n\n```typescript\nhttp.get(url as string);\n```\n\nThen check the response.\n",
+		},
+		{
+			name:    "javascript",
+			content: content,
+			lang:    "JavaScript",
+			want:    "Send a request:\n\n```javascript\nhttp.get(url);\n```\n\nThen check the response.\n",
+		},
+		{
+			name:    "no_match",
+			content: content,
+			lang:    "go",
+			want:    content,
+		},
+		{
+			name:    "none",
+			content: content,
+			want:    content,
+		},
+		{
+			name:    "several_kept",
+			content: versions,
+			lang:    "js",
+			want: "\n**Option: JavaScript (1)**\n\n```js\nv1();\n```\n\n" +
+				"**Option: JavaScript (2)**\n\n```js\nv2();\n```\n",
+		},
+		{
+			name:    "label_in_code",
+			content: "```markdown\n**Option: TypeScript**\n\n```\n",
+			lang:    "ts",
+			want:    "```markdown\n**Option: TypeScript**\n\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := PreferLang(tt.content, tt.lang); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLangFlag(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	path := filepath.Join(cacheDir, "markdown", "javascript-api", "k6-http", "get.md")
+	if err := fsext.WriteFile(afs, path, []byte(tabbedExample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "http", "get"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute(%v): %v", args, err)
+		}
+		return buf.String()
+	}
+
+	if out := run(t); !strings.Contains(out, "**Option: TypeScript**") {
+		t.Errorf("expected labelled alternatives, got:\n%s", out)
+	}
+
+	out := run(t, "--lang", "typescript")
+	if strings.Contains(out, "Option:") || strings.Contains(out, "http.get(url);") {
+		t.Errorf("expected only the TypeScript alternative, got:\n%s", out)
+	}
+
+	out = run(t, "--lang", "typescript", "--code")
+	if want := "Example 1 (typescript):\n```typescript\nhttp.get(url as string);\n```\n"; out != want {
+		t.Errorf("--code got:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,452 @@
+package docs
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by the built-in terminal renderer.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiItalic    = "\x1b[3m"
+	ansiH1        = "\x1b[1;4;35m"
+	ansiH2        = "\x1b[1;36m"
+	ansiCode      = "\x1b[33m"
+	ansiKeyword   = "\x1b[35m"
+	ansiString    = "\x1b[32m"
+	ansiNumber    = "\x1b[33m"
+	ansiComment   = "\x1b[2;3m"
+	ansiQuoteEdge = "\x1b[36m"
+)
+
+// defaultWidth is the wrapping width used when the terminal size is unknown.
+const defaultWidth = 80
+
+var (
+	reANSI       = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	reInlineCode = regexp.MustCompile("`([^`]+)`")
+	reBold       = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	reItalic     = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*)\*`)
+	reListItem   = regexp.MustCompile(`^(\s*)([-*+]|\d+\.)\s+(.*)$`)
+	reTableDelim = regexp.MustCompile(`^\s*:?-+:?\s*$`)
+	reRule       = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	reAdmonTitle = regexp.MustCompile(`^\*\*([^*:]+):\*\*\s*`)
+	reJSIdent    = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*`)
+	reJSNumber   = regexp.MustCompile(`^[0-9][0-9_]*(\.[0-9]+)?`)
+)
+
+// jsKeywords are highlighted in JavaScript and TypeScript code blocks.
+func jsKeywords() map[string]bool {
+	return map[string]bool{
+		"import": true, "export": true, "default": true, "from": true, "as": true,
+		"function": true, "const": true, "let": true, "var": true, "return": true,
+		"if": true, "else": true, "for": true, "while": true, "do": true, "switch": true,
+		"case": true, "break": true, "continue": true, "new": true, "class": true,
+		"extends": true, "this": true, "typeof": true, "instanceof": true, "await": true,
+		"async": true, "try": true, "catch": true, "finally": true, "throw": true,
+		"of": true, "in": true, "null": true, "undefined": true, "true": true, "false": true,
+		"yield": true, "delete": true, "void": true, "interface": true, "type": true,
+	}
+}
+
+// visibleWidth returns the number of terminal columns s occupies,
+// ignoring ANSI escape sequences.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(reANSI.ReplaceAllString(s, ""))
+}
+
+// padRight pads s with spaces to the given visible width.
+func padRight(s string, width int) string {
+	if n := visibleWidth(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// styleInline applies ANSI styles for inline code, bold and italic markup.
+func styleInline(s string) string {
+	s = reInlineCode.ReplaceAllString(s, ansiCode+"$1"+ansiReset)
+	s = reBold.ReplaceAllString(s, ansiBold+"$1"+ansiReset)
+	s = reItalic.ReplaceAllString(s, "$1"+ansiItalic+"$2"+ansiReset)
+	return s
+}
+
+// wrapText wraps styled text to width columns. Words longer than width are
+// kept on their own line rather than split.
+func wrapText(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var (
+		lines []string
+		line  strings.Builder
+		n     int
+	)
+	for _, word := range words {
+		wn := visibleWidth(word)
+		if n > 0 && n+1+wn > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			n = 0
+		}
+		if n > 0 {
+			line.WriteByte(' ')
+			n++
+		}
+		line.WriteString(word)
+		n += wn
+	}
+	return append(lines, line.String())
+}
+
+// RenderANSI renders transformed markdown for display on a terminal of the
+// given width: styled headings, highlighted JavaScript code, boxed
+// blockquotes (such as the admonitions produced by [Transform]), aligned
+// tables, and prose wrapped to the terminal width.
+func RenderANSI(content string, width int) string {
+	if width <= 0 {
+		width = defaultWidth
+	}
+
+	blocks := splitBlocks(content)
+	rendered := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		rendered = append(rendered, renderBlock(b, width))
+	}
+
+	if len(rendered) == 0 {
+		return ""
+	}
+	return strings.Join(rendered, "\n\n") + "\n"
+}
+
+// renderBlock renders a single markdown block.
+func renderBlock(b mdBlock, width int) string {
+	switch b.kind {
+	case blockHeading:
+		return renderHeading(b.text)
+	case blockCode:
+		return renderCode(b.text)
+	case blockTable:
+		return renderTable(b.text)
+	case blockProse:
+	}
+
+	lines := strings.Split(b.text, "\n")
+	switch {
+	case reRule.MatchString(b.text):
+		return ansiDim + strings.Repeat("─", width) + ansiReset
+	case strings.HasPrefix(strings.TrimSpace(lines[0]), ">"):
+		return renderQuote(lines, width)
+	case reListItem.MatchString(lines[0]):
+		return renderList(lines, width)
+	default:
+		return strings.Join(wrapText(styleInline(strings.Join(lines, " ")), width), "\n")
+	}
+}
+
+// renderHeading styles a heading line by level, dropping the # markers.
+func renderHeading(line string) string {
+	level, text := parseHeading(line)
+	text = styleInline(text)
+	switch level {
+	case 1:
+		return ansiH1 + text + ansiReset
+	case 2:
+		return ansiH2 + text + ansiReset
+	default:
+		return ansiBold + text + ansiReset
+	}
+}
+
+// renderQuote draws a blockquote as a box. A leading "**Title:**", as
+// produced for admonitions, becomes the box title. Fenced code inside the
+// quote is rendered as a code block; blank lines separate paragraphs.
+func renderQuote(lines []string, width int) string {
+	inner := max(width-4, 10)
+	var (
+		rows  []string
+		para  []string
+		title string
+	)
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		body := strings.Join(para, " ")
+		if len(rows) == 0 {
+			if m := reAdmonTitle.FindStringSubmatch(body); m != nil {
+				title = m[1]
+				body = body[len(m[0]):]
+			}
+		}
+		rows = append(rows, wrapText(styleInline(body), inner)...)
+		para = nil
+	}
+
+	text := make([]string, len(lines))
+	for i, l := range lines {
+		l = strings.TrimPrefix(strings.TrimLeft(l, " \t"), ">")
+		text[i] = strings.TrimPrefix(l, " ")
+	}
+	for i := 0; i < len(text); i++ {
+		if f, ok := openFence(text[i]); ok {
+			flush()
+			end := i + 1
+			for end < len(text) && !f.closes(text[end]) {
+				end++
+			}
+			end = min(end, len(text)-1)
+			rows = append(rows, strings.Split(renderCode(strings.Join(text[i:end+1], "\n")), "\n")...)
+			i = end
+			continue
+		}
+		switch {
+		case strings.TrimSpace(text[i]) == "":
+			flush()
+			if len(rows) > 0 && rows[len(rows)-1] != "" {
+				rows = append(rows, "")
+			}
+		default:
+			para = append(para, strings.TrimSpace(text[i]))
+		}
+	}
+	flush()
+	if len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+
+	var sb strings.Builder
+	edge := func(s string) string { return ansiQuoteEdge + s + ansiReset }
+
+	label := ""
+	if title != "" {
+		label = "─ " + title + " "
+	}
+	fill := strings.Repeat("─", max(inner+2-utf8.RuneCountInString(label), 0))
+	sb.WriteString(edge("╭"+label+fill+"╮") + "\n")
+	for _, l := range rows {
+		sb.WriteString(edge("│") + " " + padRight(l, inner) + " " + edge("│") + "\n")
+	}
+	sb.WriteString(edge("╰" + strings.Repeat("─", inner+2) + "╯"))
+
+	return sb.String()
+}
+
+// renderList renders list items with bullets and hanging indentation.
+func renderList(lines []string, width int) string {
+	var out []string
+	for _, l := range lines {
+		m := reListItem.FindStringSubmatch(l)
+		if m == nil {
+			// Continuation of the previous item.
+			out = append(out, wrapText(styleInline(strings.TrimSpace(l)), width)...)
+			continue
+		}
+		indent := strings.Repeat(" ", len(m[1]))
+		marker := m[2]
+		if !strings.HasSuffix(marker, ".") {
+			marker = "•"
+		}
+		prefix := indent + marker + " "
+		hang := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+		for i, wl := range wrapText(styleInline(m[3]), max(width-len(hang), 10)) {
+			if i == 0 {
+				out = append(out, prefix+wl)
+			} else {
+				out = append(out, hang+wl)
+			}
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// splitRow splits a markdown table row into trimmed cells.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// isDelimiterRow reports whether cells form a header delimiter row (| --- |).
+func isDelimiterRow(cells []string) bool {
+	for _, c := range cells {
+		if !reTableDelim.MatchString(c) {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+// renderTable aligns table columns and draws the header separator.
+func renderTable(text string) string {
+	var rows [][]string
+	header := -1
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if isDelimiterRow(cells) {
+			header = len(rows) - 1
+			continue
+		}
+		styled := make([]string, len(cells))
+		for i, c := range cells {
+			styled[i] = styleInline(c)
+		}
+		rows = append(rows, styled)
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, c := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], visibleWidth(c))
+		}
+	}
+
+	out := make([]string, 0, len(rows)+1)
+	for r, row := range rows {
+		cells := make([]string, len(widths))
+		for i := range widths {
+			c := ""
+			if i < len(row) {
+				c = row[i]
+			}
+			if r == header {
+				c = ansiBold + c + ansiReset
+			}
+			cells[i] = padRight(c, widths[i])
+		}
+		out = append(out, strings.TrimRight(strings.Join(cells, " │ "), " "))
+		if r == header {
+			seps := make([]string, len(widths))
+			for i, w := range widths {
+				seps[i] = strings.Repeat("─", w)
+			}
+			out = append(out, ansiDim+strings.Join(seps, "─┼─")+ansiReset)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderCode indents a fenced code block and highlights JavaScript and
+// TypeScript. The fence lines themselves are dropped.
+func renderCode(text string) string {
+	lang := strings.ToLower(fenceLang(strings.SplitN(text, "\n", 2)[0]))
+	lines := codeBody(text)
+
+	highlight := func(s string) string { return ansiCode + s + ansiReset }
+	switch lang {
+	case "", "javascript", "js", "typescript", "ts":
+		h := newJSHighlighter()
+		highlight = h.line
+	}
+
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = "  " + highlight(l)
+	}
+	return strings.Join(out, "\n")
+}
+
+// jsHighlighter highlights JavaScript line by line, carrying block comment
+// and template literal state across lines.
+type jsHighlighter struct {
+	keywords   map[string]bool
+	inComment  bool
+	inTemplate bool
+}
+
+func newJSHighlighter() *jsHighlighter {
+	return &jsHighlighter{keywords: jsKeywords()}
+}
+
+// line returns line with ANSI highlighting applied.
+func (h *jsHighlighter) line(line string) string {
+	var sb strings.Builder
+	s := line
+
+	for s != "" {
+		switch {
+		case h.inComment:
+			end := strings.Index(s, "*/")
+			if end < 0 {
+				sb.WriteString(ansiComment + s + ansiReset)
+				return sb.String()
+			}
+			sb.WriteString(ansiComment + s[:end+2] + ansiReset)
+			s = s[end+2:]
+			h.inComment = false
+		case h.inTemplate:
+			end := strings.Index(s, "`")
+			if end < 0 {
+				sb.WriteString(ansiString + s + ansiReset)
+				return sb.String()
+			}
+			sb.WriteString(ansiString + s[:end+1] + ansiReset)
+			s = s[end+1:]
+			h.inTemplate = false
+		case strings.HasPrefix(s, "//"):
+			sb.WriteString(ansiComment + s + ansiReset)
+			return sb.String()
+		case strings.HasPrefix(s, "/*"):
+			h.inComment = true
+			sb.WriteString(ansiComment + "/*" + ansiReset)
+			s = s[2:]
+		case s[0] == '`':
+			h.inTemplate = true
+			sb.WriteString(ansiString + "`" + ansiReset)
+			s = s[1:]
+		case s[0] == '\'' || s[0] == '"':
+			end := closingQuote(s)
+			sb.WriteString(ansiString + s[:end] + ansiReset)
+			s = s[end:]
+		default:
+			if m := reJSIdent.FindString(s); m != "" {
+				if h.keywords[m] {
+					sb.WriteString(ansiKeyword + m + ansiReset)
+				} else {
+					sb.WriteString(m)
+				}
+				s = s[len(m):]
+				continue
+			}
+			if m := reJSNumber.FindString(s); m != "" {
+				sb.WriteString(ansiNumber + m + ansiReset)
+				s = s[len(m):]
+				continue
+			}
+			_, size := utf8.DecodeRuneInString(s)
+			sb.WriteString(s[:size])
+			s = s[size:]
+		}
+	}
+
+	return sb.String()
+}
+
+// closingQuote returns the index just past the string literal starting at
+// s[0], honoring backslash escapes. Unterminated strings run to end of line.
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,213 @@
+package docs
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func stripANSI(s string) string {
+	return reANSI.ReplaceAllString(s, "")
+}
+
+func TestRenderANSI(t *testing.T) {
+	t.Parallel()
+
+	t.Run("headings_drop_markers", func(t *testing.T) {
+		t.Parallel()
+		got := RenderANSI("# Title\n\n## Sub\n", 80)
+		if !strings.Contains(got, ansiH1+"Title"+ansiReset) {
+			t.Errorf("expected styled h1, got %q", got)
+		}
+		if strings.Contains(stripANSI(got), "#") {
+			t.Errorf("heading markers should be dropped, got %q", got)
+		}
+	})
+
+	t.Run("wraps_prose", func(t *testing.T) {
+		t.Parallel()
+		got := stripANSI(RenderANSI("one two three four five six seven eight nine ten\n", 20))
+		for _, line := range strings.Split(strings.TrimSpace(got), "\n") {
+			if len(line) > 20 {
+				t.Errorf("line %q exceeds width 20", line)
+			}
+		}
+		if strings.Count(got, "\n") < 3 {
+			t.Errorf("expected prose to wrap, got %q", got)
+		}
+	})
+
+	t.Run("inline_styles", func(t *testing.T) {
+		t.Parallel()
+		got := RenderANSI("Use **bold**, *italic* and `code` here.\n", 80)
+		for _, want := range []string{ansiBold + "bold", ansiItalic + "italic", ansiCode + "code"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in %q", want, got)
+			}
+		}
+		if stripANSI(got) != "Use bold, italic and code here.\n" {
+			t.Errorf("unexpected text %q", stripANSI(got))
+		}
+	})
+
+	t.Run("admonition_box", func(t *testing.T) {
+		t.Parallel()
+		got := stripANSI(RenderANSI("> **Note:** Checks do not fail the test.\n", 40))
+		want := "╭─ Note ───────────────────────────────╮\n" +
+			"│ Checks do not fail the test.         │\n" +
+			"╰──────────────────────────────────────╯\n"
+		if got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("code_in_quote", func(t *testing.T) {
+		t.Parallel()
+		in := "> **Note:** Set a timeout:\n>\n> ```javascript\n> http.get(url, {\n>   timeout: '5s',\n> });\n> ```\n"
+		got := RenderANSI(in, 40)
+		if !strings.Contains(got, ansiString+"'5s'") {
+			t.Errorf("expected highlighted code in %q", got)
+		}
+		want := "╭─ Note ───────────────────────────────╮\n" +
+			"│ Set a timeout:                       │\n" +
+			"│                                      │\n" +
+			"│   http.get(url, {                    │\n" +
+			"│     timeout: '5s',                   │\n" +
+			"│   });                                │\n" +
+			"╰──────────────────────────────────────╯\n"
+		if stripANSI(got) != want {
+			t.Errorf("got:\n%s\nwant:\n%s", stripANSI(got), want)
+		}
+	})
+
+	t.Run("aligned_table", func(t *testing.T) {
+		t.Parallel()
+		got := stripANSI(RenderANSI("| Name | Type |\n| --- | --- |\n| url | `string` |\n| params | object |\n", 80))
+		want := "Name   │ Type\n" +
+			"───────┼───────\n" +
+			"url    │ string\n" +
+			"params │ object\n"
+		if got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("list_bullets", func(t *testing.T) {
+		t.Parallel()
+		got := stripANSI(RenderANSI("- first item\n- second item\n1. numbered\n", 80))
+		want := "• first item\n• second item\n1. numbered\n"
+		if got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("code_highlight", func(t *testing.T) {
+		t.Parallel()
+		got := RenderANSI("```javascript\nconst x = 'a'; // note\n```\n", 80)
+		for _, want := range []string{ansiKeyword + "const", ansiString + "'a'", ansiComment + "// note"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in %q", want, got)
+			}
+		}
+		if stripANSI(got) != "  const x = 'a'; // note\n" {
+			t.Errorf("unexpected text %q", stripANSI(got))
+		}
+	})
+}
+
+func TestJSHighlighterMultiline(t *testing.T) {
+	t.Parallel()
+
+	h := newJSHighlighter()
+	h.line("/* start")
+	if got := h.line("still comment */ const"); !strings.Contains(got, ansiComment+"still comment */") ||
+		!strings.Contains(got, ansiKeyword+"const") {
+		t.Errorf("block comment state not carried across lines: %q", got)
+	}
+	h.line("const s = `line one")
+	if got := h.line("line two` + 1"); !strings.Contains(got, ansiString+"line two`") ||
+		!strings.Contains(got, ansiNumber+"1") {
+		t.Errorf("template literal state not carried across lines: %q", got)
+	}
+}
+
+func TestBuiltinRendererOnTTY(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default_renders_ansi", func(t *testing.T) {
+		t.Parallel()
+		afs, cacheDir := setupTestCache(t)
+		gs := newTestGlobalState(t, afs)
+		gs.Stdout.IsTTY = true
+
+		var stdoutBuf bytes.Buffer
+		gs.Stdout.Writer = &stdoutBuf
+
+		cmd := newCmd(gs)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "http", "get"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+
+		out := stdoutBuf.String()
+		if !strings.Contains(out, ansiH2+"http.get(url)"+ansiReset) {
+			t.Errorf("expected built-in renderer output, got: %q", out)
+		}
+	})
+
+	t.Run("renderer_none_prints_raw", func(t *testing.T) {
+		t.Parallel()
+		afs, cacheDir := setupTestCache(t)
+		gs := newTestGlobalState(t, afs)
+		gs.Env["XDG_CONFIG_HOME"] = "/tmp/renderer-none-config"
+		gs.Stdout.IsTTY = true
+
+		k6Dir := filepath.Join(gs.Env["XDG_CONFIG_HOME"], "k6")
+		if err := afs.MkdirAll(k6Dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsext.WriteFile(afs, filepath.Join(k6Dir, "docs.yaml"), []byte("renderer: none\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "http", "get"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+
+		if !strings.HasPrefix(buf.String(), "## http.get(url)") {
+			t.Errorf("expected raw markdown, got: %q", buf.String())
+		}
+	})
+
+	t.Run("no_color_prints_raw", func(t *testing.T) {
+		t.Parallel()
+		afs, cacheDir := setupTestCache(t)
+		gs := newTestGlobalState(t, afs)
+		gs.Env["NO_COLOR"] = "1"
+		gs.Stdout.IsTTY = true
+
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "http", "get"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+
+		if !strings.HasPrefix(buf.String(), "## http.get(url)") {
+			t.Errorf("expected raw markdown, got: %q", buf.String())
+		}
+	})
+}
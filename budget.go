@@ -0,0 +1,201 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// charsPerToken is the rough number of characters per token used to estimate
+// output size. It errs on the side of overestimating tokens for prose.
+const charsPerToken = 4
+
+// minSectionBudget is the smallest token share a section gets when a budget
+// is spread across many sections, so each still shows its summary.
+const minSectionBudget = 60
+
+// approxTokens estimates the number of tokens in s.
+func approxTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// blockKind classifies a markdown block for budget prioritization.
+type blockKind int
+
+const (
+	blockProse blockKind = iota
+	blockHeading
+	blockTable
+	blockCode
+)
+
+// mdBlock is a blank-line separated markdown block. Fenced code is always a
+// single block, even when it contains blank lines.
+type mdBlock struct {
+	kind blockKind
+	text string
+}
+
+// splitBlocks splits markdown content into blocks.
+func splitBlocks(content string) []mdBlock {
+	var (
+		blocks []mdBlock
+		lines  []string
+		f      fence
+	)
+
+	flush := func(kind blockKind) {
+		if len(lines) == 0 {
+			return
+		}
+		blocks = append(blocks, mdBlock{kind: kind, text: strings.Join(lines, "\n")})
+		lines = nil
+	}
+
+	classify := func() blockKind {
+		if strings.HasPrefix(strings.TrimSpace(lines[0]), "|") {
+			return blockTable
+		}
+		return blockProse
+	}
+
+	for line := range strings.SplitSeq(content, "\n") {
+		inCode := f.inside()
+		isFence := f.step(line)
+		switch {
+		case inCode:
+			lines = append(lines, line)
+			if isFence {
+				flush(blockCode)
+			}
+		case isFence:
+			if len(lines) > 0 {
+				flush(classify())
+			}
+			lines = append(lines, line)
+		case strings.TrimSpace(line) == "":
+			if len(lines) > 0 {
+				flush(classify())
+			}
+		case parseHeadingLevel(line) > 0:
+			// A heading is always a block of its own.
+			if len(lines) > 0 {
+				flush(classify())
+			}
+			lines = append(lines, line)
+			flush(blockHeading)
+		default:
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) > 0 {
+		if f.inside() {
+			flush(blockCode)
+		} else {
+			flush(classify())
+		}
+	}
+
+	return blocks
+}
+
+// parseHeadingLevel returns the ATX heading level of line, or 0.
+func parseHeadingLevel(line string) int {
+	level, _ := parseHeading(line)
+	return level
+}
+
+// blockPriority ranks blocks for inclusion under a budget: headings and the
+// summary paragraph first, then parameter tables, then examples, then the
+// remaining prose.
+func blockPriority(blocks []mdBlock) []int {
+	prio := make([]int, len(blocks))
+	summarySeen := false
+	for i, b := range blocks {
+		switch b.kind {
+		case blockHeading:
+			prio[i] = 0
+		case blockTable:
+			prio[i] = 1
+		case blockCode:
+			prio[i] = 2
+		case blockProse:
+			if !summarySeen {
+				summarySeen = true
+				prio[i] = 0
+			} else {
+				prio[i] = 3
+			}
+		}
+	}
+	return prio
+}
+
+// fitBudget trims markdown content to roughly maxTokens tokens. Blocks are
+// kept in document order but selected by priority, so that a trimmed section
+// still shows its signature, summary and parameter tables. When anything is
+// dropped, a pointer to the full output is appended. A non-positive budget
+// returns content unchanged.
+func fitBudget(content string, maxTokens int, fullCmd string) string {
+	if maxTokens <= 0 || approxTokens(content) <= maxTokens {
+		return content
+	}
+
+	blocks := splitBlocks(content)
+	prio := blockPriority(blocks)
+	keep := make([]bool, len(blocks))
+
+	note := fmt.Sprintf("[Truncated to ~%d tokens. Full content: %s]", maxTokens, fullCmd)
+	remaining := maxTokens - approxTokens(note)
+
+	for p := 0; p <= 3; p++ {
+		for i, b := range blocks {
+			if prio[i] != p {
+				continue
+			}
+			cost := approxTokens(b.text) + 1
+			if cost > remaining {
+				continue
+			}
+			keep[i] = true
+			remaining -= cost
+		}
+	}
+
+	var sb strings.Builder
+	for i, b := range blocks {
+		if !keep[i] {
+			continue
+		}
+		sb.WriteString(b.text)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(note)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// fitLines keeps whole lines of s until roughly maxTokens tokens are used,
+// then appends note. It is used for list-like output such as search results.
+func fitLines(s string, maxTokens int, note string) string {
+	if maxTokens <= 0 || approxTokens(s) <= maxTokens {
+		return s
+	}
+
+	remaining := maxTokens - approxTokens(note)
+	var sb strings.Builder
+	for line := range strings.SplitSeq(s, "\n") {
+		cost := approxTokens(line) + 1
+		if cost > remaining {
+			break
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+		remaining -= cost
+	}
+	sb.WriteString(note)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
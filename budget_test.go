@@ -0,0 +1,183 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestSplitBlocks(t *testing.T) {
+	t.Parallel()
+
+	content := "# Title\nSummary line.\n\n| a | b |\n| - | - |\n\n```js\na();\n\nb();\n```\nMore prose.\n"
+	got := splitBlocks(content)
+
+	want := []mdBlock{
+		{kind: blockHeading, text: "# Title"},
+		{kind: blockProse, text: "Summary line."},
+		{kind: blockTable, text: "| a | b |\n| - | - |"},
+		{kind: blockCode, text: "```js\na();\n\nb();\n```"},
+		{kind: blockProse, text: "More prose."},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("splitBlocks() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("block[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	nested := "````markdown\n```js\na();\n```\n\n# Not a heading\n````"
+	if got := splitBlocks(nested + "\nProse.\n"); len(got) != 2 || got[0] != (mdBlock{kind: blockCode, text: nested}) {
+		t.Errorf("splitBlocks() with a nested fence = %+v", got)
+	}
+}
+
+func TestFitBudget(t *testing.T) {
+	t.Parallel()
+
+	content := "# get\n\nMake a GET request.\n\n" +
+		strings.Repeat("Long explanation that nobody needs right now. ", 20) + "\n\n" +
+		"| Parameter | Type |\n| --- | --- |\n| url | string |\n\n" +
+		"```js\nhttp.get(url);\n```\n"
+
+	t.Run("fits_unchanged", func(t *testing.T) {
+		t.Parallel()
+		if got := fitBudget(content, 10000, "k6 x docs http get"); got != content {
+			t.Errorf("content within budget should be unchanged, got:\n%s", got)
+		}
+	})
+
+	t.Run("zero_budget_unchanged", func(t *testing.T) {
+		t.Parallel()
+		if got := fitBudget(content, 0, "k6 x docs http get"); got != content {
+			t.Errorf("zero budget should be unchanged, got:\n%s", got)
+		}
+	})
+
+	t.Run("drops_prose_first", func(t *testing.T) {
+		t.Parallel()
+		got := fitBudget(content, 80, "k6 x docs http get")
+		for _, want := range []string{"# get", "Make a GET request.", "| url | string |", "http.get(url);", "Full content: k6 x docs http get"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in trimmed output:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "Long explanation") {
+			t.Errorf("expected long prose to be dropped:\n%s", got)
+		}
+		if approxTokens(got) > 80 {
+			t.Errorf("trimmed output has ~%d tokens, want <= 80", approxTokens(got))
+		}
+	})
+}
+
+func TestFitLines(t *testing.T) {
+	t.Parallel()
+
+	s := "line one\nline two\nline three\n"
+	got := fitLines(s, 6, "[more]")
+	if got != "line one\n[more]\n" {
+		t.Errorf("fitLines() = %q", got)
+	}
+}
+
+func TestMaxTokensCommand(t *testing.T) {
+	t.Parallel()
+
+	run, _ := setupCommand(t)
+
+	t.Run("section", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "budget/using-k6-scenarios.txt", run(t, "using-k6", "scenarios", "--max-tokens", "120"))
+	})
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "budget/all.txt", run(t, "--all", "--max-tokens", "400"))
+	})
+	t.Run("search", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "budget/search-k6.txt", run(t, "search", "k6", "--max-tokens", "40"))
+	})
+}
+
+func TestPrintAll_Budget(t *testing.T) {
+	t.Parallel()
+
+	afs := fsext.NewMemMapFs()
+	long := strings.Repeat("Long explanation of the topic.\n\n", 100)
+	files := map[string]string{
+		"javascript-api/k6-jslib.md": "# k6-jslib\n\n" + long,
+		"javascript-api/jslib.md":    "# jslib\n\n" + long,
+		"using-k6/empty.md":          "",
+		"using-k6/frontmatter.md":    "---\ntitle: Frontmatter only\n---\n",
+		"using-k6/checks.md":         "# Checks\n\n" + long,
+	}
+	idx := &Index{}
+	for _, rel := range []string{"javascript-api/k6-jslib.md", "javascript-api/jslib.md", "using-k6/checks.md", "using-k6/empty.md", "using-k6/frontmatter.md"} {
+		if files[rel] != "" {
+			if err := fsext.WriteFile(afs, filepath.Join("/cache", "markdown", rel), []byte(files[rel]), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		slug := strings.TrimSuffix(rel, ".md")
+		idx.Sections = append(idx.Sections, Section{Slug: slug, RelPath: rel, Category: CategoryFromSlug(slug)})
+	}
+	idx.reindex()
+
+	var buf bytes.Buffer
+	printAll(afs, &buf, idx, "/cache", "v1.0.0", 2*minSectionBudget)
+	out := buf.String()
+
+	// The short form "jslib" opens javascript-api/jslib, so the full slug is
+	// printed for javascript-api/k6-jslib.
+	if !strings.Contains(out, "Full content: k6 x docs javascript-api/k6-jslib") {
+		t.Errorf("expected a resolvable pointer to k6-jslib:\n%s", out)
+	}
+	// The empty sections are never printed, so they are not counted as
+	// omitted, even when only the transform empties them.
+	if !strings.Contains(out, "[1 more sections omitted.") {
+		t.Errorf("expected 1 omitted section:\n%s", out)
+	}
+}
+
+func TestPrintSection_Budget(t *testing.T) {
+	t.Parallel()
+
+	afs := fsext.NewMemMapFs()
+	body := strings.Repeat("See [the guide](https://grafana.com/docs/guide/) for details.\n\n", 40)
+	if err := fsext.WriteFile(afs, "/cache/markdown/using-k6/checks.md", []byte("# Checks\n\n"+body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx := &Index{}
+	parent := Section{Slug: "using-k6/checks", RelPath: "using-k6/checks.md", Category: "using-k6"}
+	for i := range 5 {
+		slug := fmt.Sprintf("using-k6/checks/check-with-a-long-name-%d", i)
+		parent.Children = append(parent.Children, slug)
+		idx.Sections = append(idx.Sections, Section{Slug: slug, Category: "using-k6"})
+	}
+	idx.Sections = append(idx.Sections, parent)
+	idx.reindex()
+
+	const budget = 300
+	var buf bytes.Buffer
+	notes := strings.Repeat("Our checks always name the endpoint. ", 10)
+	printSection(afs, &buf, idx, &idx.Sections[len(idx.Sections)-1], notes, "/cache", "v1.0.0", budget)
+	out := buf.String()
+
+	// The footnotes, notes and footer are printed in full and count against
+	// the budget, so the whole output fits.
+	for _, want := range []string{"[^1]: https://grafana.com/docs/guide/", "Our checks", "Subtopics:", "[Truncated to"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q:\n%s", want, out)
+		}
+	}
+	if got := approxTokens(out); got > budget {
+		t.Errorf("output is ~%d tokens, want at most %d:\n%s", got, budget, out)
+	}
+}
@@ -0,0 +1,131 @@
+package docs
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Frontmatter holds the YAML fields extracted from each doc file.
+type Frontmatter struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Weight      int    `yaml:"weight"`
+
+	// Deprecated, Experimental and Since set the markers of a page, see
+	// DetectMarkers.
+	Deprecated   bool   `yaml:"deprecated"`
+	Experimental bool   `yaml:"experimental"`
+	Since        string `yaml:"since"`
+}
+
+// ParseFrontmatter extracts YAML frontmatter from content.
+func ParseFrontmatter(content string) (Frontmatter, error) {
+	var fm Frontmatter
+	if !strings.HasPrefix(content, "---\n") {
+		return fm, nil
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return fm, nil
+	}
+	yamlBlock := deduplicateYAMLKeys(content[4 : 4+end])
+	if err := yaml.Unmarshal([]byte(yamlBlock), &fm); err != nil {
+		return fm, fmt.Errorf("parse yaml: %w", err)
+	}
+	return fm, nil
+}
+
+// deduplicateYAMLKeys removes duplicate top-level YAML keys, keeping only
+// the first occurrence of each key. This handles the ~60 k6-docs files that
+// have duplicate "description:" keys, which cause yaml.v3 to error.
+func deduplicateYAMLKeys(yamlBlock string) string {
+	seen := make(map[string]bool)
+	var lines []string
+	for line := range strings.SplitSeq(yamlBlock, "\n") {
+		if idx := strings.Index(line, ":"); idx > 0 && len(line) > 0 && line[0] != ' ' && line[0] != '\t' && line[0] != '#' {
+			key := strings.TrimSpace(line[:idx])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SlugFromRelPath derives the slug from a relative path.
+// Rules: strip .md, if _index.md use parent dir, path uses forward slashes.
+func SlugFromRelPath(relPath string) string {
+	relPath = filepath.ToSlash(relPath)
+	base := filepath.Base(relPath)
+	if base == "_index.md" {
+		return filepath.ToSlash(filepath.Dir(relPath))
+	}
+	return strings.TrimSuffix(relPath, ".md")
+}
+
+// CategoryFromSlug extracts the first path segment as the category.
+func CategoryFromSlug(slug string) string {
+	if before, _, found := strings.Cut(slug, "/"); found {
+		return before
+	}
+	return slug
+}
+
+// PopulateChildren sets the Children field for each _index section.
+// A child is a section whose slug starts with parent slug + "/" and has
+// no further "/" after that prefix (direct child only).
+func PopulateChildren(sections []Section) {
+	for i := range sections {
+		if !sections[i].IsIndex {
+			continue
+		}
+
+		parentSlug := sections[i].Slug
+		prefix := parentSlug + "/"
+
+		// Collect direct children.
+		type child struct {
+			slug   string
+			weight int
+		}
+		var children []child
+
+		for j := range sections {
+			if i == j {
+				continue
+			}
+			s := sections[j].Slug
+			if !strings.HasPrefix(s, prefix) {
+				continue
+			}
+			remainder := s[len(prefix):]
+			if strings.Contains(remainder, "/") {
+				continue
+			}
+			children = append(children, child{slug: s, weight: sections[j].Weight})
+		}
+
+		sort.Slice(children, func(a, b int) bool {
+			return children[a].weight < children[b].weight
+		})
+
+		slugs := make([]string, len(children))
+		for k, c := range children {
+			slugs[k] = c.slug
+		}
+		sections[i].Children = slugs
+	}
+
+	// Ensure non-index sections have empty (non-nil) Children.
+	for i := range sections {
+		if sections[i].Children == nil {
+			sections[i].Children = []string{}
+		}
+	}
+}
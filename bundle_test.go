@@ -0,0 +1,198 @@
+package docs
+
+import "testing"
+
+func TestSlugDerivation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		relPath string
+		want    string
+	}{
+		{"javascript-api/k6-http/_index.md", "javascript-api/k6-http"},
+		{"javascript-api/k6-http/get.md", "javascript-api/k6-http/get"},
+		{"using-k6/scenarios/_index.md", "using-k6/scenarios"},
+		{"using-k6/checks.md", "using-k6/checks"},
+		{"examples/_index.md", "examples"},
+		{"reference/glossary.md", "reference/glossary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			t.Parallel()
+			got := SlugFromRelPath(tt.relPath)
+			if got != tt.want {
+				t.Errorf("SlugFromRelPath(%q) = %q, want %q", tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategoryDerivation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{"javascript-api/k6-http/get", "javascript-api"},
+		{"using-k6/checks", "using-k6"},
+		{"examples", "examples"},
+		{"reference/glossary", "reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			t.Parallel()
+			got := CategoryFromSlug(tt.slug)
+			if got != tt.want {
+				t.Errorf("CategoryFromSlug(%q) = %q, want %q", tt.slug, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    Frontmatter
+	}{
+		{
+			name:    "typical frontmatter",
+			content: "---\ntitle: 'Checks'\ndescription: 'Validate conditions.'\nweight: 400\n---\n\n# Checks",
+			want:    Frontmatter{Title: "Checks", Description: "Validate conditions.", Weight: 400},
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Just markdown",
+			want:    Frontmatter{},
+		},
+		{
+			name:    "frontmatter with extra fields",
+			content: "---\ntitle: 'Test'\naliases:\n  - /old/path\nweight: 5\n---\ncontent",
+			want:    Frontmatter{Title: "Test", Weight: 5},
+		},
+		{
+			name:    "empty content",
+			content: "",
+			want:    Frontmatter{},
+		},
+		{
+			name:    "duplicate keys keeps first",
+			content: "---\ntitle: 'First'\ndescription: 'First desc'\ndescription: 'Second desc'\nweight: 10\n---\n\n# Body",
+			want:    Frontmatter{Title: "First", Description: "First desc", Weight: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseFrontmatter(tt.content)
+			if err != nil {
+				t.Fatalf("ParseFrontmatter: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeduplicateYAMLKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "no duplicates",
+			input: "title: 'Hello'\ndescription: 'World'\nweight: 1",
+			want:  "title: 'Hello'\ndescription: 'World'\nweight: 1",
+		},
+		{
+			name:  "duplicate description",
+			input: "title: 'Hello'\ndescription: 'First'\ndescription: 'Second'\nweight: 1",
+			want:  "title: 'Hello'\ndescription: 'First'\nweight: 1",
+		},
+		{
+			name:  "preserves indented lines",
+			input: "title: 'Hello'\naliases:\n  - /old\n  - /older\ntitle: 'Duplicate'",
+			want:  "title: 'Hello'\naliases:\n  - /old\n  - /older",
+		},
+		{
+			name:  "preserves comments",
+			input: "# comment\ntitle: 'Hello'\ntitle: 'Dup'",
+			want:  "# comment\ntitle: 'Hello'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := deduplicateYAMLKeys(tt.input)
+			if got != tt.want {
+				t.Errorf("deduplicateYAMLKeys():\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopulateChildren(t *testing.T) {
+	t.Parallel()
+
+	sections := []Section{
+		{Slug: "using-k6", IsIndex: true, Weight: 1},
+		{Slug: "using-k6/checks", Weight: 400},
+		{Slug: "using-k6/thresholds", Weight: 200},
+		{Slug: "using-k6/scenarios", IsIndex: true, Weight: 300},
+		{Slug: "using-k6/scenarios/executors", IsIndex: true, Weight: 1},
+		{Slug: "using-k6/scenarios/executors/shared-iterations", Weight: 1},
+	}
+
+	PopulateChildren(sections)
+
+	// using-k6 should have checks, thresholds, scenarios as direct children.
+	// Sorted by weight: thresholds (200), scenarios (300), checks (400).
+	parent := sections[0]
+	if len(parent.Children) != 3 {
+		t.Fatalf("using-k6 children: got %d, want 3", len(parent.Children))
+	}
+	if parent.Children[0] != "using-k6/thresholds" {
+		t.Errorf("Children[0] = %q, want %q", parent.Children[0], "using-k6/thresholds")
+	}
+	if parent.Children[1] != "using-k6/scenarios" {
+		t.Errorf("Children[1] = %q, want %q", parent.Children[1], "using-k6/scenarios")
+	}
+	if parent.Children[2] != "using-k6/checks" {
+		t.Errorf("Children[2] = %q, want %q", parent.Children[2], "using-k6/checks")
+	}
+
+	// using-k6/scenarios should have executors as only direct child.
+	scenarios := sections[3]
+	if len(scenarios.Children) != 1 {
+		t.Fatalf("scenarios children: got %d, want 1", len(scenarios.Children))
+	}
+	if scenarios.Children[0] != "using-k6/scenarios/executors" {
+		t.Errorf("Children[0] = %q, want %q", scenarios.Children[0], "using-k6/scenarios/executors")
+	}
+
+	// using-k6/scenarios/executors should have shared-iterations.
+	executors := sections[4]
+	if len(executors.Children) != 1 {
+		t.Fatalf("executors children: got %d, want 1", len(executors.Children))
+	}
+
+	// Non-index leaf nodes should have empty (non-nil) children.
+	checks := sections[1]
+	if checks.Children == nil {
+		t.Error("leaf Children should be non-nil")
+	}
+	if len(checks.Children) != 0 {
+		t.Errorf("leaf Children count = %d, want 0", len(checks.Children))
+	}
+}
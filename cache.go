@@ -22,14 +22,23 @@ type HTTPClient interface {
 	Get(url string) (*http.Response, error)
 }
 
+// dataDir returns the directory k6 keeps local data in, ~/.local/share/k6/.
+func dataDir(env map[string]string) (string, error) {
+	home, err := homeDirFromEnv(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "k6"), nil
+}
+
 // CacheDir returns the local cache directory for a given docs version.
 // The layout is ~/.local/share/k6/docs/{version}/.
 func CacheDir(env map[string]string, version string) (string, error) {
-	home, err := homeDirFromEnv(env)
+	dir, err := dataDir(env)
 	if err != nil {
 		return "", fmt.Errorf("cache dir: %w", err)
 	}
-	return filepath.Join(home, ".local", "share", "k6", "docs", version), nil
+	return filepath.Join(dir, "docs", version), nil
 }
 
 // IsCached reports whether the docs for the given version are already cached.
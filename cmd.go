@@ -7,18 +7,34 @@ import (
 	"io"
 	"net/http"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/lib/fsext"
+	"golang.org/x/term"
 )
 
 func newCmd(gs *state.GlobalState) *cobra.Command {
-	return newDocsCmd(gs)
+	return newDocsCmd(gs, nil)
 }
 
-func newDocsCmd(gs *state.GlobalState) *cobra.Command {
-	var opts docsOpts
+// NewCommand returns a constructor for the docs command whose transform
+// pipeline is adjusted by customize, so that downstream forks can register
+// their own stages:
+//
+//	subcommand.RegisterExtension("docs", docs.NewCommand(func(p *docs.Pipeline) error {
+//		return p.Insert(docs.NewTransformStage("redact", "Redact hostnames", redact), "whitespace")
+//	}))
+func NewCommand(customize func(*Pipeline) error) func(*state.GlobalState) *cobra.Command {
+	return func(gs *state.GlobalState) *cobra.Command {
+		return newDocsCmd(gs, customize)
+	}
+}
+
+func newDocsCmd(gs *state.GlobalState, customize func(*Pipeline) error) *cobra.Command {
+	opts := docsOpts{customize: customize}
 
 	cmd := &cobra.Command{
 		Use:   "docs [topic] [subtopic...]",
@@ -32,8 +48,25 @@ func newDocsCmd(gs *state.GlobalState) *cobra.Command {
 
 	cmd.Flags().BoolVar(&opts.list, "list", false, "List subtopics instead of showing content")
 	cmd.Flags().BoolVar(&opts.all, "all", false, "Print all documentation")
+	cmd.Flags().BoolVar(&opts.outline, "outline", false, "Print the heading outline of a topic")
+	cmd.Flags().StringVar(&opts.heading, "heading", "", "Print only the subsection under this heading (or use topic#anchor)")
+	cmd.Flags().BoolVar(&opts.code, "code", false, "Print only the code examples of a topic")
+	cmd.Flags().StringVar(&opts.out, "out", "", "Write the code examples of a topic as files to this directory")
 	cmd.PersistentFlags().StringVar(&opts.version, "version", "", "Override k6 version for docs lookup")
 	cmd.PersistentFlags().StringVar(&opts.cacheDir, "cache-dir", "", "Override cache directory")
+	cmd.PersistentFlags().IntVar(&opts.maxTokens, "max-tokens", 0, "Trim output to roughly this many tokens (0 = no limit)")
+	cmd.PersistentFlags().BoolVar(&opts.noPager, "no-pager", false, "Do not pipe long output through a pager")
+	cmd.PersistentFlags().StringVar(&opts.format, "format", formatMarkdown, "Output format: markdown or text")
+	cmd.PersistentFlags().StringVar(&opts.lang, "lang", "",
+		"Show only this language where a code example has alternatives (e.g. typescript)")
+	cmd.PersistentFlags().BoolVar(&opts.hideDeprecated, "hide-deprecated", false,
+		"Leave deprecated topics out of lists and search results")
+	cmd.PersistentFlags().BoolVar(&opts.experimentalOnly, "experimental-only", false,
+		"List only experimental topics in lists and search results")
+	cmd.PersistentFlags().StringSliceVar(&opts.enableTransforms, "enable-transform", nil,
+		"Enable a transform stage disabled in docs.yaml (see k6 x docs transforms)")
+	cmd.PersistentFlags().StringSliceVar(&opts.disableTransforms, "disable-transform", nil,
+		"Disable a transform stage, e.g. links to keep URLs (see k6 x docs transforms)")
 
 	searchCmd := &cobra.Command{
 		Use:   "search <term>",
@@ -44,19 +77,48 @@ func newDocsCmd(gs *state.GlobalState) *cobra.Command {
 		},
 	}
 	cmd.AddCommand(searchCmd)
+	cmd.AddCommand(newExportCmd(gs, &opts))
+	cmd.AddCommand(newDiffCmd(gs, &opts))
+	cmd.AddCommand(newWhatsNewCmd(gs, &opts))
+	cmd.AddCommand(newExtensionsCmd(gs, &opts))
+	cmd.AddCommand(newNoteCmd(gs, &opts))
+	cmd.AddCommand(newRecentCmd(gs, &opts))
+	cmd.AddCommand(newBookmarkCmd(gs, &opts))
+	cmd.AddCommand(newTransformsCmd(gs, &opts))
+	cmd.AddCommand(newSigCmd(gs, &opts))
 
 	return cmd
 }
 
 type docsOpts struct {
-	list     bool
-	all      bool
-	version  string
-	cacheDir string
+	list      bool
+	all       bool
+	outline   bool
+	heading   string
+	code      bool
+	out       string
+	version   string
+	cacheDir  string
+	maxTokens int
+	noPager   bool
+	format    string
+	lang      string
+
+	hideDeprecated   bool
+	experimentalOnly bool
+
+	enableTransforms  []string
+	disableTransforms []string
+	// customize adjusts the transform pipeline, see NewCommand.
+	customize func(*Pipeline) error
 }
 
 func runSearch(gs *state.GlobalState, cmd *cobra.Command, args []string, opts *docsOpts) error {
-	version, cacheDir, idx, err := setup(gs, opts.version, opts.cacheDir)
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+
+	version, cacheDir, idx, err := setup(gs, opts)
 	if err != nil {
 		return err
 	}
@@ -66,83 +128,136 @@ func runSearch(gs *state.GlobalState, cmd *cobra.Command, args []string, opts *d
 		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
 	}
 
-	isTTY := gs.Stdout.IsTTY
-	baseW := cmd.OutOrStdout()
-	var buf *bytes.Buffer
-	w := baseW
-
-	if cfg.Renderer != "" && isTTY {
-		buf = &bytes.Buffer{}
-		w = buf
-	}
+	w, render := newOutput(gs, cmd, cfg, opts)
 
 	term := strings.Join(args, " ")
-	printSearch(gs.FS, w, idx, term, cacheDir, version)
-	return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+	printSearch(gs.FS, w, idx, term, cacheDir, version, opts.maxTokens)
+	return render()
 }
 
 func runDocs(gs *state.GlobalState, cmd *cobra.Command, args []string, opts *docsOpts) error {
-	version, cacheDir, idx, err := setup(gs, opts.version, opts.cacheDir)
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+
+	version, cacheDir, idx, err := setup(gs, opts)
 	if err != nil {
 		return err
 	}
 
-	isTTY := gs.Stdout.IsTTY
-	logMode(gs, isTTY)
+	logMode(gs, gs.Stdout.IsTTY)
 
 	cfg, cfgErr := loadConfig(gs.FS, gs.Env)
 	if cfgErr != nil && gs != nil {
 		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
 	}
-
-	baseW := cmd.OutOrStdout()
-	var buf *bytes.Buffer
-	w := baseW
-
-	if cfg.Renderer != "" && isTTY {
-		buf = &bytes.Buffer{}
-		w = buf
-	}
+	w, render := newOutput(gs, cmd, cfg, opts)
 
 	if opts.all {
-		printAll(gs.FS, w, idx, cacheDir, version)
-		return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+		printAll(gs.FS, w, idx, cacheDir, version, opts.maxTokens)
+		return render()
 	}
 
 	if opts.list && len(args) == 0 {
 		printTopLevelList(w, idx)
-		return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+		return render()
 	}
 
 	if len(args) == 0 {
 		printTOC(w, idx, version)
-		return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+		return render()
 	}
 
 	if args[0] == "best-practices" {
-		if err := printBestPractices(gs.FS, w, cacheDir, version); err != nil {
+		if err := printBestPractices(gs.FS, w, idx, cacheDir, version); err != nil {
 			return err
 		}
-		return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+		return render()
 	}
 
-	slug := ResolveWithLookup(args, func(s string) bool {
-		_, ok := idx.Lookup(s)
-		return ok
-	})
+	args, heading := splitAnchor(args)
+	if opts.heading != "" {
+		heading = opts.heading
+	}
 
-	sec, ok := idx.Lookup(slug)
-	if !ok {
-		return fmt.Errorf("topic not found: %s", strings.Join(args, " "))
+	sec, err := openTopic(gs, cmd.ErrOrStderr(), idx, args)
+	if err != nil {
+		return err
+	}
+
+	notes := readNotes(gs.FS, gs.Env, sec.Slug)
+	lang := preferredLang(cfg, opts)
+	if err := printTopic(gs.FS, w, idx, sec, heading, notes, cacheDir, version, lang, opts); err != nil {
+		return err
+	}
+	if err := render(); err != nil {
+		return err
+	}
+
+	// History is for people reading at a terminal; agents and scripts run
+	// lookups often and concurrently, so their lookups are not recorded.
+	if gs.Stdout.IsTTY {
+		if err := recordHistory(gs.FS, gs.Env, sec.Slug); err != nil {
+			gs.Logger.Debugf("docs: record history: %v", err)
+		}
+	}
+	return nil
+}
+
+// resolveTopic finds the section named by args, falling back to the closest
+// fuzzy match, which is announced on notice.
+func resolveTopic(notice io.Writer, idx *Index, args []string) (*Section, error) {
+	if sec, ok := idx.Lookup(idx.resolve(args)); ok {
+		return sec, nil
 	}
+	return resolveFuzzy(notice, idx, args)
+}
 
-	if opts.list {
-		printList(w, idx, slug)
-		return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+// openTopic resolves the topic to print. The argument "-" reopens the most
+// recently opened topic.
+func openTopic(gs *state.GlobalState, notice io.Writer, idx *Index, args []string) (*Section, error) {
+	var sec *Section
+	if len(args) == 1 && args[0] == lastTopic {
+		slug, err := lastSlug(gs.FS, gs.Env)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if sec, ok = idx.Lookup(slug); !ok {
+			return nil, fmt.Errorf("topic not found: %s", slugToArgs(slug))
+		}
+	} else {
+		var err error
+		if sec, err = resolveTopic(notice, idx, args); err != nil {
+			return nil, err
+		}
 	}
+	return sec, nil
+}
 
-	printSection(gs.FS, w, idx, sec, cacheDir, version)
-	return pipeRenderer(cmd.Context(), buf, gs.Stdout.Writer, baseW, gs.Stderr, cfg.Renderer)
+// printTopic prints a resolved section in the mode selected by opts. The
+// user's notes on the section are shown with its full content. lang is the
+// preferred language for code examples, which --code and --out apply before
+// extracting the code blocks.
+func printTopic(
+	afs fsext.Fs, w io.Writer, idx *Index, sec *Section, heading, notes, cacheDir, version, lang string,
+	opts *docsOpts,
+) error {
+	switch {
+	case opts.list:
+		printList(w, idx, sec.Slug)
+	case opts.out != "":
+		return writeCode(afs, w, idx, sec, opts.out, cacheDir, version, lang)
+	case opts.code:
+		printCode(afs, w, idx, sec, cacheDir, version, lang)
+	case opts.outline:
+		printOutline(afs, w, idx, sec, cacheDir, version)
+	case heading != "":
+		return printHeading(afs, w, idx, sec, heading, cacheDir, version)
+	default:
+		printSection(afs, w, idx, sec, notes, cacheDir, version, opts.maxTokens)
+	}
+	return nil
 }
 
 func logMode(gs *state.GlobalState, isTTY bool) {
@@ -156,6 +271,90 @@ func logMode(gs *state.GlobalState, isTTY bool) {
 	}
 }
 
+// rendererNone is the renderer config value that disables rendering.
+const rendererNone = "none"
+
+// newOutput returns the writer commands print to and a function that flushes
+// it. With a preferred language, code examples with alternatives are reduced
+// to that language, except with --code and --out, whose code blocks are
+// extracted after being reduced. With --format text, output is converted to
+// plain text. On a TTY, markdown output is buffered and passed through the
+// configured renderer, or the built-in ANSI renderer when none is configured,
+// and any output is then passed through the pager when it is taller than the
+// terminal. Otherwise output is written as is, so agents always get plain
+// markdown.
+func newOutput(gs *state.GlobalState, cmd *cobra.Command, cfg docsConfig, opts *docsOpts) (io.Writer, func() error) {
+	baseW := cmd.OutOrStdout()
+	text := opts.format == formatText
+	lang := preferredLang(cfg, opts)
+	if opts.code || opts.out != "" {
+		lang = ""
+	}
+
+	render, pager := false, ""
+	if gs.Stdout.IsTTY {
+		render = !text && cfg.Renderer != rendererNone && (cfg.Renderer != "" || gs.Env["NO_COLOR"] == "")
+		if !opts.noPager {
+			pager = pagerCommand(cfg, gs.Env)
+		}
+	}
+	if !text && !render && pager == "" && lang == "" {
+		return baseW, func() error { return nil }
+	}
+
+	buf := &bytes.Buffer{}
+	return buf, func() error {
+		if buf.Len() == 0 {
+			return nil
+		}
+		if lang != "" {
+			filtered := PreferLang(buf.String(), lang)
+			buf.Reset()
+			buf.WriteString(filtered)
+		}
+
+		ctx := cmd.Context()
+		height := terminalHeight(gs)
+
+		switch {
+		case text:
+			return page(ctx, []byte(RenderText(buf.String())), baseW, gs.Stdout.Writer, gs.Stderr, pager, height)
+		case !render:
+			return page(ctx, buf.Bytes(), baseW, gs.Stdout.Writer, gs.Stderr, pager, height)
+		case cfg.Renderer == "":
+			rendered := RenderANSI(buf.String(), terminalWidth(gs))
+			return page(ctx, []byte(rendered), gs.Stdout.Writer, gs.Stdout.Writer, gs.Stderr, pager, height)
+		default:
+			var rendered bytes.Buffer
+			if err := pipeRenderer(ctx, buf, &rendered, baseW, gs.Stderr, cfg.Renderer); err != nil {
+				return err
+			}
+			return page(ctx, rendered.Bytes(), gs.Stdout.Writer, gs.Stdout.Writer, gs.Stderr, pager, height)
+		}
+	}
+}
+
+// preferredLang returns the language --lang or, without it, the lang config
+// key selects for code examples with alternatives.
+func preferredLang(cfg docsConfig, opts *docsOpts) string {
+	if opts.lang != "" {
+		return opts.lang
+	}
+	return cfg.Lang
+}
+
+// terminalWidth returns the width of the terminal attached to stdout.
+// $COLUMNS takes precedence; the default width is used when neither is known.
+func terminalWidth(gs *state.GlobalState) int {
+	if cols, err := strconv.Atoi(gs.Env["COLUMNS"]); err == nil && cols > 0 {
+		return cols
+	}
+	if w, _, err := term.GetSize(gs.Stdout.RawOutFd); err == nil && w > 0 {
+		return w
+	}
+	return defaultWidth
+}
+
 func pipeRenderer(
 	ctx context.Context, buf *bytes.Buffer, stdout, fallback, stderr io.Writer, renderer string,
 ) error {
@@ -171,9 +370,12 @@ func pipeRenderer(
 		return err
 	}
 
+	// Buffer the renderer output so a renderer that fails midway does not
+	// leave partial output behind before the raw fallback is written.
+	var rendered bytes.Buffer
 	rc := exec.CommandContext(ctx, parts[0], parts[1:]...) //nolint:gosec // user-configured renderer
 	rc.Stdin = bytes.NewReader(raw)
-	rc.Stdout = stdout
+	rc.Stdout = &rendered
 	rc.Stderr = stderr
 
 	if err := rc.Run(); err != nil {
@@ -181,14 +383,15 @@ func pipeRenderer(
 		return writeErr
 	}
 
-	return nil
+	_, err := stdout.Write(rendered.Bytes())
+	return err
 }
 
-// setup resolves the version, ensures docs are cached, and loads the index.
-// It checks flags, then env vars, then auto-detection for both version and
-// cache directory.
-func setup(gs *state.GlobalState, versionFlag, cacheDirFlg string) (version, cacheDir string, idx *Index, err error) {
-	version = versionFlag
+// setup resolves the version, ensures docs are cached, and loads the index
+// with its transform pipeline. It checks flags, then env vars, then
+// auto-detection for both version and cache directory.
+func setup(gs *state.GlobalState, opts *docsOpts) (version, cacheDir string, idx *Index, err error) {
+	version = opts.version
 	if version == "" {
 		version = gs.Env["K6_DOCS_VERSION"]
 	}
@@ -199,7 +402,7 @@ func setup(gs *state.GlobalState, versionFlag, cacheDirFlg string) (version, cac
 		}
 	}
 
-	cacheDir = cacheDirFlg
+	cacheDir = opts.cacheDir
 	if cacheDir == "" {
 		cacheDir = gs.Env["K6_DOCS_CACHE_DIR"]
 	}
@@ -216,5 +419,17 @@ func setup(gs *state.GlobalState, versionFlag, cacheDirFlg string) (version, cac
 		return "", "", nil, fmt.Errorf("load index: %w", err)
 	}
 
+	// An invalid config is reported by the commands that use it.
+	cfg, _ := loadConfig(gs.FS, gs.Env)
+	if len(cfg.Overlays) > 0 {
+		mergeOverlays(gs.FS, gs.Env, idx, cfg.Overlays, func(err error) {
+			gs.Logger.Warnf("docs: %v", err)
+		})
+	}
+	if idx.pipeline, err = transformPipeline(cfg, opts); err != nil {
+		return "", "", nil, err
+	}
+	idx.filter = sectionFilter{hideDeprecated: opts.hideDeprecated, experimentalOnly: opts.experimentalOnly}
+
 	return version, cacheDir, idx, nil
 }
@@ -4,6 +4,9 @@
 //   - markdown/ — transformed .md files
 //   - sections.json — structured index of all sections
 //   - best_practices.md — a comprehensive best practices guide
+//
+// With --k6-versions or --all-versions it builds a bundle per version, each
+// into its own subdirectory of the output directory.
 package main
 
 import (
@@ -19,79 +22,117 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
+	"runtime"
 	"strings"
 
 	docs "github.com/grafana/xk6-subcommand-docs"
 	"go.k6.io/k6/lib/fsext"
-	"gopkg.in/yaml.v3"
 )
 
-// frontmatter holds the YAML fields we extract from each doc file.
-type frontmatter struct {
-	Title       string `yaml:"title"`
-	Description string `yaml:"description"`
-	Weight      int    `yaml:"weight"`
-}
-
 func main() {
 	log.SetFlags(0)
 
 	var (
-		k6Version  string
-		k6DocsPath string
-		outputDir  string
+		k6Version   string
+		k6Versions  string
+		allVersions bool
+		k6DocsPath  string
+		outputDir   string
+		showReport  bool
+		strict      bool
+		jobs        int
 	)
 
-	flag.StringVar(&k6Version, "k6-version", "", "k6 docs version (e.g. v1.5.x) — required")
+	flag.StringVar(&k6Version, "k6-version", "", "k6 docs version (e.g. v1.5.x)")
+	flag.StringVar(&k6Versions, "k6-versions", "",
+		"comma-separated k6 docs versions (e.g. v1.4.x,v1.5.x), each built into <output-dir>/<version>")
+	flag.BoolVar(&allVersions, "all-versions", false,
+		"build every version in the k6-docs repo, each into <output-dir>/<version>")
+	flag.IntVar(&jobs, "jobs", runtime.NumCPU(), "number of versions built at a time")
 	flag.StringVar(&k6DocsPath, "k6-docs-path", "", "local path to k6-docs repo (cloned if empty)")
 	flag.StringVar(&outputDir, "output-dir", "dist/", "output directory")
+	flag.BoolVar(&showReport, "report", false, "print a validation report of broken links and index anomalies")
+	flag.BoolVar(&strict, "strict", false, "print the validation report and fail if it lists any problem")
 	flag.Parse()
 
-	if k6Version == "" {
-		log.Fatal("--k6-version is required")
-	}
-
+	var (
+		reports []*report
+		err     error
+	)
 	afs := fsext.NewOsFs()
-	if err := run(k6Version, k6DocsPath, outputDir, afs); err != nil {
+	switch {
+	case countSet(k6Version != "", k6Versions != "", allVersions) != 1:
+		log.Fatal("one of --k6-version, --k6-versions or --all-versions is required")
+	case k6Version != "":
+		var rep *report
+		rep, err = run(k6Version, k6DocsPath, outputDir, afs)
+		reports = []*report{rep}
+	default:
+		reports, err = runVersions(splitVersions(k6Versions), allVersions, jobs, k6DocsPath, outputDir, afs)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
+
+	problems := 0
+	for _, rep := range reports {
+		if showReport || strict {
+			rep.print(os.Stderr) //nolint:forbidigo
+		}
+		problems += rep.count()
+	}
+	if strict && problems > 0 {
+		log.Fatalf("--strict: %d problems in the docs", problems)
+	}
 }
 
+// run builds the bundle for k6Version into outputDir and returns the
+// validation report of the docs.
+//
 //nolint:forbidigo
-func run(k6Version, k6DocsPath, outputDir string, afs fsext.Fs) error {
+func run(k6Version, k6DocsPath, outputDir string, afs fsext.Fs) (*report, error) {
 	// Step 1: ensure we have the k6-docs repo.
 	docsPath, cleanup, err := ensureDocsRepo(k6DocsPath, afs, os.Stderr, os.MkdirTemp)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if cleanup != nil {
 		defer cleanup()
 	}
 
+	return build(afs, docsPath, k6Version, outputDir)
+}
+
+// build builds the bundle for k6Version from the k6-docs repo at docsPath
+// into outputDir and returns the validation report of the docs.
+//
+//nolint:forbidigo
+func build(afs fsext.Fs, docsPath, k6Version, outputDir string) (*report, error) {
 	// The k6-docs repo uses wildcard directories (e.g. "v1.6.x"), so convert
 	// exact versions like "v1.6.1" to the wildcard form for the path lookup.
 	docsVersion := docs.MapToWildcard(k6Version)
 	versionRoot := filepath.Join(docsPath, "docs", "sources", "k6", docsVersion)
 	if _, err := afs.Stat(filepath.Clean(versionRoot)); err != nil {
-		return fmt.Errorf("version root not found: %w", err)
+		return nil, fmt.Errorf("version root not found: %w", err)
 	}
 
 	// Step 2: build shared content map.
 	sharedContent, err := buildSharedContentMap(afs, filepath.Join(versionRoot, "shared"))
 	if err != nil {
-		return fmt.Errorf("build shared content: %w", err)
+		return nil, fmt.Errorf("build shared content: %w", err)
 	}
 
 	// Step 3: walk documentation files and collect sections.
+	rep := &report{version: k6Version}
 	markdownDir := filepath.Join(outputDir, "markdown")
-	sections, err := walkAndProcess(afs, versionRoot, markdownDir, sharedContent)
+	sections, err := walkAndProcess(afs, versionRoot, markdownDir, sharedContent, rep)
 	if err != nil {
-		return fmt.Errorf("walk docs: %w", err)
+		return nil, fmt.Errorf("walk docs: %w", err)
 	}
 
 	// Step 4: populate children.
-	populateChildren(sections)
+	docs.PopulateChildren(sections)
+	rep.checkIndex(sections)
 
 	// Step 5: write sections.json.
 	idx := docs.Index{
@@ -99,16 +140,16 @@ func run(k6Version, k6DocsPath, outputDir string, afs fsext.Fs) error {
 		Sections: sections,
 	}
 	if err := writeSectionsJSON(afs, outputDir, idx); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Step 6: write best_practices.md.
 	if err := writeBestPractices(afs, outputDir); err != nil {
-		return err
+		return nil, err
 	}
 
-	_, _ = fmt.Fprintln(os.Stderr, "Done: sections written")
-	return nil
+	_, _ = fmt.Fprintf(os.Stderr, "Done: sections written for %s\n", k6Version)
+	return rep, nil
 }
 
 // ensureDocsRepo returns the path to the k6-docs repo. If k6DocsPath is empty,
@@ -178,65 +219,11 @@ func buildSharedContentMap(afs fsext.Fs, sharedDir string) (map[string]string, e
 	return m, err
 }
 
-// parseFrontmatter extracts YAML frontmatter from content.
-func parseFrontmatter(content string) (frontmatter, error) {
-	var fm frontmatter
-	if !strings.HasPrefix(content, "---\n") {
-		return fm, nil
-	}
-	end := strings.Index(content[4:], "\n---")
-	if end == -1 {
-		return fm, nil
-	}
-	yamlBlock := deduplicateYAMLKeys(content[4 : 4+end])
-	if err := yaml.Unmarshal([]byte(yamlBlock), &fm); err != nil {
-		return fm, fmt.Errorf("parse yaml: %w", err)
-	}
-	return fm, nil
-}
-
-// deduplicateYAMLKeys removes duplicate top-level YAML keys, keeping only
-// the first occurrence of each key. This handles the ~60 k6-docs files that
-// have duplicate "description:" keys, which cause yaml.v3 to error.
-func deduplicateYAMLKeys(yamlBlock string) string {
-	seen := make(map[string]bool)
-	var lines []string
-	for line := range strings.SplitSeq(yamlBlock, "\n") {
-		if idx := strings.Index(line, ":"); idx > 0 && len(line) > 0 && line[0] != ' ' && line[0] != '\t' && line[0] != '#' {
-			key := strings.TrimSpace(line[:idx])
-			if seen[key] {
-				continue
-			}
-			seen[key] = true
-		}
-		lines = append(lines, line)
-	}
-	return strings.Join(lines, "\n")
-}
-
-// slugFromRelPath derives the slug from a relative path.
-// Rules: strip .md, if _index.md use parent dir, path uses forward slashes.
-func slugFromRelPath(relPath string) string {
-	relPath = filepath.ToSlash(relPath)
-	base := filepath.Base(relPath)
-	if base == "_index.md" {
-		return filepath.ToSlash(filepath.Dir(relPath))
-	}
-	return strings.TrimSuffix(relPath, ".md")
-}
-
-// categoryFromSlug extracts the first path segment as the category.
-func categoryFromSlug(slug string) string {
-	if before, _, found := strings.Cut(slug, "/"); found {
-		return before
-	}
-	return slug
-}
-
 // walkAndProcess walks the version root, processes included .md files,
-// and returns the collected sections.
+// and returns the collected sections. Problems found on the way are added
+// to rep.
 func walkAndProcess(
-	afs fsext.Fs, versionRoot, markdownDir string, sharedContent map[string]string,
+	afs fsext.Fs, versionRoot, markdownDir string, sharedContent map[string]string, rep *report,
 ) ([]docs.Section, error) {
 	// Use a map to deduplicate sections by slug. When a slug collision
 	// occurs (e.g. cookiejar.md and cookiejar/_index.md both produce
@@ -246,7 +233,7 @@ func walkAndProcess(
 	var slugOrder []string
 
 	err := fsext.Walk(afs, versionRoot, func(path string, info fs.FileInfo, err error) error {
-		return processEntry(afs, path, info, err, versionRoot, markdownDir, sharedContent, sectionMap, &slugOrder)
+		return processEntry(afs, path, info, err, versionRoot, markdownDir, sharedContent, sectionMap, &slugOrder, rep)
 	})
 
 	// Rebuild the slice in walk order.
@@ -265,6 +252,7 @@ func processEntry(
 	sharedContent map[string]string,
 	sectionMap map[string]docs.Section,
 	slugOrder *[]string,
+	rep *report,
 ) error {
 	if err != nil {
 		return err
@@ -302,15 +290,18 @@ func processEntry(
 		return fmt.Errorf("read %s: %w", rel, err)
 	}
 
-	fm, err := parseFrontmatter(string(content))
+	fm, err := docs.ParseFrontmatter(string(content))
 	if err != nil {
-		log.Printf("warning: %s: %v", rel, err)
+		// Versions are built concurrently, so say which one this is.
+		log.Printf("warning: %s: %s: %v", rep.version, rel, err)
+		rep.yamlErrors = append(rep.yamlErrors, problem{file: rel, detail: err.Error()})
 	}
+	rep.checkContent(rel, string(content), sharedContent)
 
 	transformed := docs.PrepareTransform(string(content), sharedContent)
 
-	slug := slugFromRelPath(rel)
-	category := categoryFromSlug(slug)
+	slug := docs.SlugFromRelPath(rel)
+	category := docs.CategoryFromSlug(slug)
 	isIndex := filepath.Base(path) == "_index.md"
 
 	// Write transformed markdown.
@@ -330,13 +321,22 @@ func processEntry(
 		Weight:      fm.Weight,
 		Category:    category,
 		IsIndex:     isIndex,
+		Markers:     docs.DetectMarkers(slug, transformed, fm),
+	}
+	if category == "javascript-api" {
+		sec.Signature = docs.ParseSignature(fm.Title, transformed)
 	}
 
 	// Handle slug collisions: prefer _index.md over plain .md files.
 	if existing, ok := sectionMap[slug]; ok {
+		kept, dropped := existing.RelPath, rel
 		if isIndex && !existing.IsIndex {
 			sectionMap[slug] = sec
+			kept, dropped = rel, existing.RelPath
 		}
+		rep.collisions = append(rep.collisions, problem{
+			file: dropped, detail: fmt.Sprintf("same slug %s as %s, which is kept", slug, kept),
+		})
 	} else {
 		*slugOrder = append(*slugOrder, slug)
 		sectionMap[slug] = sec
@@ -345,59 +345,6 @@ func processEntry(
 	return nil
 }
 
-// populateChildren sets the Children field for each _index section.
-// A child is a section whose slug starts with parent slug + "/" and has
-// no further "/" after that prefix (direct child only).
-func populateChildren(sections []docs.Section) {
-	for i := range sections {
-		if !sections[i].IsIndex {
-			continue
-		}
-
-		parentSlug := sections[i].Slug
-		prefix := parentSlug + "/"
-
-		// Collect direct children.
-		type child struct {
-			slug   string
-			weight int
-		}
-		var children []child
-
-		for j := range sections {
-			if i == j {
-				continue
-			}
-			s := sections[j].Slug
-			if !strings.HasPrefix(s, prefix) {
-				continue
-			}
-			remainder := s[len(prefix):]
-			if strings.Contains(remainder, "/") {
-				continue
-			}
-			children = append(children, child{slug: s, weight: sections[j].Weight})
-		}
-
-		sort.Slice(children, func(a, b int) bool {
-			return children[a].weight < children[b].weight
-		})
-
-		slugs := make([]string, len(children))
-		for k, c := range children {
-			slugs[k] = c.slug
-		}
-		sections[i].Children = slugs
-	}
-
-	// Ensure non-index sections have empty (non-nil) Children.
-	for i := range sections {
-		if sections[i].Children == nil {
-			sections[i].Children = []string{}
-		}
-	}
-}
-
 // writeSectionsJSON writes the index to sections.json in the output directory.
 func writeSectionsJSON(afs fsext.Fs, outputDir string, idx docs.Index) error {
 	if err := afs.MkdirAll(outputDir, 0o750); err != nil {
@@ -261,7 +261,7 @@ func TestRunWithMockDocs(t *testing.T) {
 	version := "v0.99.x"
 	outputDir := "/output"
 
-	if err := run(version, docsPath, outputDir, afs); err != nil {
+	if _, err := run(version, docsPath, outputDir, afs); err != nil {
 		t.Fatalf("run: %v", err)
 	}
 
@@ -377,7 +377,7 @@ func TestTransformedMarkdownContent(t *testing.T) {
 	version := "v0.99.x"
 	outputDir := "/output-transformed"
 
-	if err := run(version, docsPath, outputDir, afs); err != nil {
+	if _, err := run(version, docsPath, outputDir, afs); err != nil {
 		t.Fatalf("run: %v", err)
 	}
 
@@ -468,7 +468,7 @@ func TestBestPracticesWritten(t *testing.T) {
 	version := "v0.99.x"
 	outputDir := "/output-bestpractices"
 
-	if err := run(version, docsPath, outputDir, afs); err != nil {
+	if _, err := run(version, docsPath, outputDir, afs); err != nil {
 		t.Fatalf("run: %v", err)
 	}
 
@@ -501,201 +501,6 @@ func TestBestPracticesWritten(t *testing.T) {
 	}
 }
 
-func TestSlugDerivation(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		relPath string
-		want    string
-	}{
-		{"javascript-api/k6-http/_index.md", "javascript-api/k6-http"},
-		{"javascript-api/k6-http/get.md", "javascript-api/k6-http/get"},
-		{"using-k6/scenarios/_index.md", "using-k6/scenarios"},
-		{"using-k6/checks.md", "using-k6/checks"},
-		{"examples/_index.md", "examples"},
-		{"reference/glossary.md", "reference/glossary"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.relPath, func(t *testing.T) {
-			t.Parallel()
-			got := slugFromRelPath(tt.relPath)
-			if got != tt.want {
-				t.Errorf("slugFromRelPath(%q) = %q, want %q", tt.relPath, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestCategoryDerivation(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		slug string
-		want string
-	}{
-		{"javascript-api/k6-http/get", "javascript-api"},
-		{"using-k6/checks", "using-k6"},
-		{"examples", "examples"},
-		{"reference/glossary", "reference"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.slug, func(t *testing.T) {
-			t.Parallel()
-			got := categoryFromSlug(tt.slug)
-			if got != tt.want {
-				t.Errorf("categoryFromSlug(%q) = %q, want %q", tt.slug, got, tt.want)
-			}
-		})
-	}
-}
-
-func TestParseFrontmatter(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name    string
-		content string
-		want    frontmatter
-	}{
-		{
-			name:    "typical frontmatter",
-			content: "---\ntitle: 'Checks'\ndescription: 'Validate conditions.'\nweight: 400\n---\n\n# Checks",
-			want:    frontmatter{Title: "Checks", Description: "Validate conditions.", Weight: 400},
-		},
-		{
-			name:    "no frontmatter",
-			content: "# Just markdown",
-			want:    frontmatter{},
-		},
-		{
-			name:    "frontmatter with extra fields",
-			content: "---\ntitle: 'Test'\naliases:\n  - /old/path\nweight: 5\n---\ncontent",
-			want:    frontmatter{Title: "Test", Weight: 5},
-		},
-		{
-			name:    "empty content",
-			content: "",
-			want:    frontmatter{},
-		},
-		{
-			name:    "duplicate keys keeps first",
-			content: "---\ntitle: 'First'\ndescription: 'First desc'\ndescription: 'Second desc'\nweight: 10\n---\n\n# Body",
-			want:    frontmatter{Title: "First", Description: "First desc", Weight: 10},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			got, err := parseFrontmatter(tt.content)
-			if err != nil {
-				t.Fatalf("parseFrontmatter: %v", err)
-			}
-			if got != tt.want {
-				t.Errorf("got %+v, want %+v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestDeduplicateYAMLKeys(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name  string
-		input string
-		want  string
-	}{
-		{
-			name:  "no duplicates",
-			input: "title: 'Hello'\ndescription: 'World'\nweight: 1",
-			want:  "title: 'Hello'\ndescription: 'World'\nweight: 1",
-		},
-		{
-			name:  "duplicate description",
-			input: "title: 'Hello'\ndescription: 'First'\ndescription: 'Second'\nweight: 1",
-			want:  "title: 'Hello'\ndescription: 'First'\nweight: 1",
-		},
-		{
-			name:  "preserves indented lines",
-			input: "title: 'Hello'\naliases:\n  - /old\n  - /older\ntitle: 'Duplicate'",
-			want:  "title: 'Hello'\naliases:\n  - /old\n  - /older",
-		},
-		{
-			name:  "preserves comments",
-			input: "# comment\ntitle: 'Hello'\ntitle: 'Dup'",
-			want:  "# comment\ntitle: 'Hello'",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			got := deduplicateYAMLKeys(tt.input)
-			if got != tt.want {
-				t.Errorf("deduplicateYAMLKeys():\ngot:  %q\nwant: %q", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestPopulateChildren(t *testing.T) {
-	t.Parallel()
-
-	sections := []docs.Section{
-		{Slug: "using-k6", IsIndex: true, Weight: 1},
-		{Slug: "using-k6/checks", Weight: 400},
-		{Slug: "using-k6/thresholds", Weight: 200},
-		{Slug: "using-k6/scenarios", IsIndex: true, Weight: 300},
-		{Slug: "using-k6/scenarios/executors", IsIndex: true, Weight: 1},
-		{Slug: "using-k6/scenarios/executors/shared-iterations", Weight: 1},
-	}
-
-	populateChildren(sections)
-
-	// using-k6 should have checks, thresholds, scenarios as direct children.
-	// Sorted by weight: thresholds (200), scenarios (300), checks (400).
-	parent := sections[0]
-	if len(parent.Children) != 3 {
-		t.Fatalf("using-k6 children: got %d, want 3", len(parent.Children))
-	}
-	if parent.Children[0] != "using-k6/thresholds" {
-		t.Errorf("Children[0] = %q, want %q", parent.Children[0], "using-k6/thresholds")
-	}
-	if parent.Children[1] != "using-k6/scenarios" {
-		t.Errorf("Children[1] = %q, want %q", parent.Children[1], "using-k6/scenarios")
-	}
-	if parent.Children[2] != "using-k6/checks" {
-		t.Errorf("Children[2] = %q, want %q", parent.Children[2], "using-k6/checks")
-	}
-
-	// using-k6/scenarios should have executors as only direct child.
-	scenarios := sections[3]
-	if len(scenarios.Children) != 1 {
-		t.Fatalf("scenarios children: got %d, want 1", len(scenarios.Children))
-	}
-	if scenarios.Children[0] != "using-k6/scenarios/executors" {
-		t.Errorf("Children[0] = %q, want %q", scenarios.Children[0], "using-k6/scenarios/executors")
-	}
-
-	// using-k6/scenarios/executors should have shared-iterations.
-	executors := sections[4]
-	if len(executors.Children) != 1 {
-		t.Fatalf("executors children: got %d, want 1", len(executors.Children))
-	}
-
-	// Non-index leaf nodes should have empty (non-nil) children.
-	checks := sections[1]
-	if checks.Children == nil {
-		t.Error("leaf Children should be non-nil")
-	}
-	if len(checks.Children) != 0 {
-		t.Errorf("leaf Children count = %d, want 0", len(checks.Children))
-	}
-}
-
 func TestSlugCollisionPrefersIndex(t *testing.T) {
 	t.Parallel()
 
@@ -718,9 +523,14 @@ func TestSlugCollisionPrefersIndex(t *testing.T) {
 		"---\ntitle: 'set'\nweight: 1\n---\n\nSet a cookie.\n")
 
 	outputDir := "/output-collision"
-	if err := run(version, root, outputDir, afs); err != nil {
+	rep, err := run(version, root, outputDir, afs)
+	if err != nil {
 		t.Fatalf("run: %v", err)
 	}
+	// The collision is resolved, so it is a warning that passes --strict.
+	if rep.count() != 0 || len(rep.collisions) != 1 {
+		t.Errorf("count = %d, collisions = %v, want no problems and one warning", rep.count(), rep.collisions)
+	}
 
 	data, err := fsext.ReadFile(afs, filepath.Join(outputDir, "sections.json"))
 	if err != nil {
@@ -754,6 +564,103 @@ func TestSlugCollisionPrefersIndex(t *testing.T) {
 	}
 }
 
+func TestMarkers(t *testing.T) {
+	t.Parallel()
+
+	afs, docsPath := setupMockDocs(t)
+	versionRoot := filepath.Join(docsPath, "docs", "sources", "k6", "v0.99.x")
+	writeFile(t, afs, filepath.Join(versionRoot, "javascript-api", "k6-experimental", "redis.md"), `---
+title: 'k6/experimental/redis'
+weight: 30
+---
+
+{{< admonition type="caution" >}}
+
+This module has been promoted to a k6 extension and is deprecated since k6 v0.56.0.
+
+{{< /admonition >}}
+`)
+	writeFile(t, afs, filepath.Join(versionRoot, "using-k6", "tags.md"), `---
+title: 'Tags'
+weight: 600
+---
+
+Tags are available since k6 v0.52.0.
+`)
+	writeFile(t, afs, filepath.Join(versionRoot, "using-k6", "scenarios", "arrival-rate.md"), `---
+title: 'Arrival rate'
+weight: 10
+---
+
+{{< admonition type="note" >}}
+
+This executor is available since k6 v0.27.0.
+
+{{< /admonition >}}
+`)
+
+	outputDir := "/output-markers"
+	if _, err := run("v0.99.x", docsPath, outputDir, afs); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	_, bySlug := loadOutputIndex(t, afs, outputDir, "v0.99.x")
+
+	tests := map[string]docs.Markers{
+		"javascript-api/k6-experimental/redis": {Deprecated: true, Experimental: true},
+		"using-k6/tags":                        {},
+		"using-k6/scenarios/arrival-rate":      {Since: "v0.27.0"},
+		"using-k6/checks":                      {},
+	}
+	for slug, want := range tests {
+		if got := bySlug[slug].Markers; got != want {
+			t.Errorf("%s: markers = %+v, want %+v", slug, got, want)
+		}
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	t.Parallel()
+
+	afs, docsPath := setupMockDocs(t)
+	versionRoot := filepath.Join(docsPath, "docs", "sources", "k6", "v0.99.x")
+	writeFile(t, afs, filepath.Join(versionRoot, "javascript-api", "k6-http", "del.md"), `---
+title: 'del( url, [body], [params] )'
+weight: 30
+---
+
+| Parameter         | Type   | Description                  |
+| ----------------- | ------ | ---------------------------- |
+| url               | string | Request URL.                 |
+| body (optional)   | string | Request body.                |
+
+### Returns
+
+| Type     | Description           |
+| -------- | --------------------- |
+| Response | HTTP Response object. |
+`)
+
+	outputDir := "/output-signatures"
+	if _, err := run("v0.99.x", docsPath, outputDir, afs); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	_, bySlug := loadOutputIndex(t, afs, outputDir, "v0.99.x")
+
+	sig := bySlug["javascript-api/k6-http/del"].Signature
+	if sig == nil {
+		t.Fatal("expected a signature for del")
+	}
+	if got := sig.Line(); got != "del(url, [body], [params]) → Response" {
+		t.Errorf("signature = %q", got)
+	}
+	if len(sig.Params) != 2 || !sig.Params[1].Optional {
+		t.Errorf("params = %+v", sig.Params)
+	}
+	if sig := bySlug["using-k6/checks"].Signature; sig != nil {
+		t.Errorf("expected no signature outside the JavaScript API, got %+v", sig)
+	}
+}
+
 func TestRunWithRealDocs(t *testing.T) {
 	t.Parallel()
 
@@ -771,7 +678,7 @@ func TestRunWithRealDocs(t *testing.T) {
 	outputDir := filepath.Join(t.TempDir(), "real-output")
 	version := "v1.5.x"
 
-	if err := run(version, k6DocsPath, outputDir, afs); err != nil {
+	if _, err := run(version, k6DocsPath, outputDir, afs); err != nil {
 		t.Fatalf("run with real docs: %v", err)
 	}
 
@@ -830,7 +737,7 @@ func TestRunWithExactVersionNoVPrefix(t *testing.T) {
 	afs, docsPath := setupMockDocs(t)
 	outputDir := "/output-novprefix"
 
-	if err := run("0.99.3", docsPath, outputDir, afs); err != nil {
+	if _, err := run("0.99.3", docsPath, outputDir, afs); err != nil {
 		t.Fatalf("run with bare version (no v prefix): %v", err)
 	}
 
@@ -859,7 +766,7 @@ func TestMissingVersion(t *testing.T) {
 	afs, docsPath := setupMockDocs(t)
 	outputDir := "/output-missing"
 
-	err := run("v999.999.x", docsPath, outputDir, afs)
+	_, err := run("v999.999.x", docsPath, outputDir, afs)
 	if err == nil {
 		t.Fatal("expected error for missing version, got nil")
 	}
@@ -877,7 +784,7 @@ func TestRunWithExactVersion(t *testing.T) {
 	afs, docsPath := setupMockDocs(t)
 	outputDir := "/output-exact"
 
-	if err := run("v0.99.3", docsPath, outputDir, afs); err != nil {
+	if _, err := run("v0.99.3", docsPath, outputDir, afs); err != nil {
 		t.Fatalf("run with exact version: %v", err)
 	}
 
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+
+	docs "github.com/grafana/xk6-subcommand-docs"
+)
+
+// problem is an anomaly found in a docs file while building the bundle.
+type problem struct {
+	// file is the path of the docs file relative to the version root.
+	file string
+	// detail describes the problem, e.g. the lookup that did not resolve.
+	detail string
+}
+
+// report collects the anomalies found while building a bundle, which would
+// otherwise ship unnoticed.
+type report struct {
+	// version is the k6 version of the docs.
+	version string
+
+	unresolvedShared []problem
+	brokenLinks      []problem
+	yamlErrors       []problem
+	emptyTitles      []string
+	orphans          []string
+	// emptyDescs are warnings rather than problems: many upstream pages
+	// have no description, and lists fall back to their title.
+	emptyDescs []string
+	// collisions are warnings too: a page and a directory index with the
+	// same slug (e.g. cookiejar.md and cookiejar/_index.md) are resolved by
+	// keeping the index, but the dropped file is worth knowing about.
+	collisions []problem
+
+	// links maps each docs file to the internal link paths it contains.
+	links map[string][]string
+}
+
+// checkContent records the unresolved shared lookups and the internal links
+// of a docs file. The links are checked by checkIndex once all slugs are
+// known.
+func (r *report) checkContent(rel, content string, sharedContent map[string]string) {
+	for _, lookup := range docs.SharedLookups(content) {
+		if _, ok := sharedContent[lookup]; !ok {
+			r.unresolvedShared = append(r.unresolvedShared, problem{file: rel, detail: lookup})
+		}
+	}
+	if paths := docs.InternalLinkPaths(content); len(paths) > 0 {
+		if r.links == nil {
+			r.links = make(map[string][]string)
+		}
+		r.links[rel] = paths
+	}
+}
+
+// checkIndex records the internal links to missing slugs, from every file
+// processed including those dropped by a slug collision, the sections with
+// empty titles or descriptions, and the sections that cannot be reached
+// from a top-level category through Children.
+func (r *report) checkIndex(sections []docs.Section) {
+	bySlug := make(map[string]*docs.Section, len(sections))
+	for i := range sections {
+		bySlug[sections[i].Slug] = &sections[i]
+	}
+
+	reachable := make(map[string]bool, len(sections))
+	var visit func(slug string)
+	visit = func(slug string) {
+		sec, ok := bySlug[slug]
+		if !ok || reachable[slug] {
+			return
+		}
+		reachable[slug] = true
+		for _, child := range sec.Children {
+			visit(child)
+		}
+	}
+	for _, sec := range sections {
+		if sec.Category == sec.Slug {
+			visit(sec.Slug)
+		}
+	}
+
+	for _, sec := range sections {
+		if sec.Title == "" {
+			r.emptyTitles = append(r.emptyTitles, sec.Slug)
+		}
+		if sec.Description == "" {
+			r.emptyDescs = append(r.emptyDescs, sec.Slug)
+		}
+		if !reachable[sec.Slug] {
+			r.orphans = append(r.orphans, sec.Slug)
+		}
+	}
+	sort.Strings(r.orphans)
+
+	for _, file := range slices.Sorted(maps.Keys(r.links)) {
+		for _, path := range r.links[file] {
+			if _, ok := bySlug[path]; !ok {
+				r.brokenLinks = append(r.brokenLinks, problem{file: file, detail: path})
+			}
+		}
+	}
+}
+
+// count returns the number of problems in the report, which --strict fails
+// on. Warnings are not counted.
+func (r *report) count() int {
+	return len(r.unresolvedShared) + len(r.brokenLinks) + len(r.yamlErrors) + len(r.emptyTitles) + len(r.orphans)
+}
+
+// warnings returns the number of warnings in the report.
+func (r *report) warnings() int {
+	return len(r.emptyDescs) + len(r.collisions)
+}
+
+// print writes the report, a list per kind of problem, then the warnings.
+func (r *report) print(w io.Writer) {
+	summary := fmt.Sprintf("%d problems", r.count())
+	if r.count() == 0 {
+		summary = "no problems found"
+	}
+	if r.warnings() > 0 {
+		summary += fmt.Sprintf(", %d warnings", r.warnings())
+	}
+	_, _ = fmt.Fprintf(w, "Validation report for %s: %s\n", r.version, summary)
+
+	problems := func(title string, ps []problem) {
+		if len(ps) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "\n%s (%d):\n", title, len(ps))
+		for _, p := range ps {
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", p.file, p.detail)
+		}
+	}
+	slugs := func(title string, ss []string) {
+		if len(ss) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "\n%s (%d):\n", title, len(ss))
+		for _, s := range ss {
+			_, _ = fmt.Fprintf(w, "  %s\n", s)
+		}
+	}
+
+	problems("Unresolved shared lookups", r.unresolvedShared)
+	problems("Internal links to missing pages", r.brokenLinks)
+	problems("Invalid frontmatter", r.yamlErrors)
+	slugs("Sections without a title", r.emptyTitles)
+	slugs("Sections not reachable from a category", r.orphans)
+	problems("Warning: slug collisions", r.collisions)
+	slugs("Warning: sections without a description", r.emptyDescs)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	afs, docsPath := setupMockDocs(t)
+	versionRoot := filepath.Join(docsPath, "docs", "sources", "k6", "v0.99.x")
+	writeFile(t, afs, filepath.Join(versionRoot, "javascript-api", "k6-http", "post.md"), `---
+title: 'post( url, [body], [params] )'
+weight: 20
+---
+
+{{< docs/shared source="k6" lookup="javascript-api/missing.md" version="<K6_VERSION>" >}}
+
+See [get](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/get/#returns),
+[put](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/put/) and
+[the install guide](https://grafana.com/docs/k6/<K6_VERSION>/set-up/install-k6/).
+
+`+"```javascript\n// [del](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/del/)\n```"+`
+`)
+	writeFile(t, afs, filepath.Join(versionRoot, "using-k6", "broken.md"), `---
+title: 'Broken
+description: 'Unterminated quote.'
+---
+`)
+	// using-k6/checks.md is dropped for this index, but its links are checked.
+	writeFile(t, afs, filepath.Join(versionRoot, "using-k6", "checks.md"), `---
+title: 'Checks'
+description: 'Checks validate boolean conditions.'
+---
+
+See [groups](https://grafana.com/docs/k6/<K6_VERSION>/using-k6/groups/).
+`)
+	writeFile(t, afs, filepath.Join(versionRoot, "using-k6", "checks", "_index.md"), `---
+title: 'Checks'
+description: 'Checks, with subtopics.'
+weight: 100
+---
+`)
+
+	rep, err := run("v0.99.x", docsPath, "/output-report", afs)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	post := filepath.Join("javascript-api", "k6-http", "post.md")
+	if len(rep.unresolvedShared) != 1 || rep.unresolvedShared[0] != (problem{post, "javascript-api/missing.md"}) {
+		t.Errorf("unresolvedShared = %v, want the missing lookup of %s", rep.unresolvedShared, post)
+	}
+	// Links to excluded pages and links in code are not checked.
+	checks := filepath.Join("using-k6", "checks.md")
+	wantLinks := []problem{{post, "javascript-api/k6-http/put"}, {checks, "using-k6/groups"}}
+	if !slices.Equal(rep.brokenLinks, wantLinks) {
+		t.Errorf("brokenLinks = %v, want %v", rep.brokenLinks, wantLinks)
+	}
+	if len(rep.yamlErrors) != 1 || rep.yamlErrors[0].file != filepath.Join("using-k6", "broken.md") {
+		t.Errorf("yamlErrors = %v, want using-k6/broken.md", rep.yamlErrors)
+	}
+	if len(rep.collisions) != 1 || rep.collisions[0].file != filepath.Join("using-k6", "checks.md") {
+		t.Errorf("collisions = %v, want using-k6/checks.md dropped", rep.collisions)
+	}
+	if !slices.Contains(rep.emptyTitles, "using-k6/broken") {
+		t.Errorf("emptyTitles = %v, want using-k6/broken", rep.emptyTitles)
+	}
+	if !slices.Contains(rep.emptyDescs, "javascript-api/k6-http/post") {
+		t.Errorf("emptyDescs = %v, want javascript-api/k6-http/post", rep.emptyDescs)
+	}
+	// The reference category index is not in the bundle, only the glossary.
+	if !slices.Equal(rep.orphans, []string{"reference/glossary"}) {
+		t.Errorf("orphans = %v, want [reference/glossary]", rep.orphans)
+	}
+
+	var buf bytes.Buffer
+	rep.print(&buf)
+	out := buf.String()
+	for _, want := range []string{
+		fmt.Sprintf("Validation report for v0.99.x: %d problems, %d warnings\n", rep.count(), rep.warnings()),
+		"Unresolved shared lookups (1):\n  " + post + ": javascript-api/missing.md\n",
+		"Internal links to missing pages (2):\n  " + post + ": javascript-api/k6-http/put\n",
+		"Sections not reachable from a category (1):\n  reference/glossary\n",
+		"Warning: slug collisions (1):\n  " + checks + ": same slug using-k6/checks as using-k6/checks/_index.md, which is kept\n",
+		"Warning: sections without a description (",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReport_NoProblems(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rep = report{version: "v1.5.x"}
+		buf bytes.Buffer
+	)
+	rep.print(&buf)
+	if got, want := buf.String(), "Validation report for v1.5.x: no problems found\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReport_DescriptionsAreWarnings(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rep = report{version: "v1.5.x", emptyDescs: []string{"using-k6/checks"}}
+		buf bytes.Buffer
+	)
+	if rep.count() != 0 {
+		t.Errorf("count = %d, want sections without a description not counted", rep.count())
+	}
+	rep.print(&buf)
+	want := "Validation report for v1.5.x: no problems found, 1 warnings\n\n" +
+		"Warning: sections without a description (1):\n  using-k6/checks\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
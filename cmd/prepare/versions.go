@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// countSet returns how many of flags are set.
+func countSet(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+// splitVersions splits a comma-separated list of versions, dropping empty
+// entries and duplicates.
+func splitVersions(s string) []string {
+	var versions []string
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" && !slices.Contains(versions, v) {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// discoverVersions returns the versions with docs in the k6-docs repo at
+// docsPath, i.e. the docs/sources/k6/v* directories, sorted.
+func discoverVersions(afs fsext.Fs, docsPath string) ([]string, error) {
+	dir := filepath.Join(docsPath, "docs", "sources", "k6")
+	entries, err := fsext.ReadDir(afs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("list versions: %w", err)
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "v") {
+			versions = append(versions, e.Name())
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no v* version directories in %s", dir)
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// runVersions builds a bundle per version, or per version in the repo if
+// all is set, each into outputDir/<version>. The k6-docs repo is cloned
+// once for all of them, but each build walks and parses its own version
+// directory. Up to jobs bundles are built at a time: each build reads and
+// writes its own directories only. It returns the validation reports in the
+// order of the versions.
+//
+//nolint:forbidigo
+func runVersions(
+	versions []string, all bool, jobs int, k6DocsPath, outputDir string, afs fsext.Fs,
+) ([]*report, error) {
+	docsPath, cleanup, err := ensureDocsRepo(k6DocsPath, afs, os.Stderr, os.MkdirTemp)
+	if err != nil {
+		return nil, err
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	if all {
+		if versions, err = discoverVersions(afs, docsPath); err != nil {
+			return nil, err
+		}
+	}
+	if len(versions) == 0 {
+		return nil, errors.New("no k6 versions to build")
+	}
+
+	var (
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, max(jobs, 1))
+		reports = make([]*report, len(versions))
+		errs    = make([]error, len(versions))
+	)
+	for i, version := range versions {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			reports[i], errs[i] = build(afs, docsPath, version, filepath.Join(outputDir, version))
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", version, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitVersions(t *testing.T) {
+	t.Parallel()
+
+	got := splitVersions(" v1.4.x,v1.5.x,, v1.4.x ")
+	if want := []string{"v1.4.x", "v1.5.x"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRunVersions(t *testing.T) {
+	t.Parallel()
+
+	afs, docsPath := setupMockDocs(t)
+	sources := filepath.Join(docsPath, "docs", "sources", "k6")
+	writeFile(t, afs, filepath.Join(sources, "v0.98.x", "using-k6", "_index.md"), `---
+title: 'Using k6 (v0.98)'
+description: 'Older docs.'
+weight: 02
+---
+`)
+	// Directories other than versions are not built.
+	writeFile(t, afs, filepath.Join(sources, "next", "using-k6", "_index.md"), "---\ntitle: 'Next'\n---\n")
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		outputDir := "/output-all"
+		// One job at a time still builds every version.
+		reports, err := runVersions(nil, true, 1, docsPath, outputDir, afs)
+		if err != nil {
+			t.Fatalf("runVersions: %v", err)
+		}
+		if len(reports) != 2 || reports[0].version != "v0.98.x" || reports[1].version != "v0.99.x" {
+			t.Fatalf("reports = %v, want one for v0.98.x and v0.99.x", reports)
+		}
+
+		_, older := loadOutputIndex(t, afs, filepath.Join(outputDir, "v0.98.x"), "v0.98.x")
+		if got := older["using-k6"].Title; got != "Using k6 (v0.98)" {
+			t.Errorf("v0.98.x using-k6 title = %q, want the v0.98.x docs", got)
+		}
+		_, newer := loadOutputIndex(t, afs, filepath.Join(outputDir, "v0.99.x"), "v0.99.x")
+		if _, ok := newer["javascript-api/k6-http/get"]; !ok {
+			t.Error("v0.99.x bundle is missing javascript-api/k6-http/get")
+		}
+		if _, err := afs.Stat(filepath.Join(outputDir, "next")); err == nil {
+			t.Error("the next directory should not be built")
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+
+		outputDir := "/output-list"
+		reports, err := runVersions([]string{"v0.99.3"}, false, 4, docsPath, outputDir, afs)
+		if err != nil {
+			t.Fatalf("runVersions: %v", err)
+		}
+		if len(reports) != 1 || reports[0].version != "v0.99.3" {
+			t.Fatalf("reports = %v, want one for v0.99.3", reports)
+		}
+		loadOutputIndex(t, afs, filepath.Join(outputDir, "v0.99.3"), "v0.99.3")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := runVersions([]string{"v0.99.x", "v999.0.x"}, false, 2, docsPath, "/output-missing-versions", afs)
+		if err == nil || !strings.Contains(err.Error(), "v999.0.x: version root not found") {
+			t.Errorf("err = %v, want the missing version named", err)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// CodeBlock is a fenced code block extracted from a section.
+type CodeBlock struct {
+	Lang string
+	Code string
+}
+
+// ExtractCodeBlocks returns the fenced code blocks in content in document order.
+// An unterminated fence runs to the end of the content.
+func ExtractCodeBlocks(content string) []CodeBlock {
+	var (
+		blocks  []CodeBlock
+		current *CodeBlock
+		lines   []string
+		f       fence
+	)
+
+	for line := range strings.SplitSeq(content, "\n") {
+		if f.step(line) {
+			if current == nil {
+				current = &CodeBlock{Lang: fenceLang(line)}
+				lines = lines[:0]
+				continue
+			}
+			current.Code = strings.Join(lines, "\n") + "\n"
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		if current != nil {
+			lines = append(lines, line)
+		}
+	}
+
+	if current != nil && len(lines) > 0 {
+		current.Code = strings.Join(lines, "\n") + "\n"
+		blocks = append(blocks, *current)
+	}
+
+	return blocks
+}
+
+// fenceLang returns the language of an opening fence line: the first word of
+// its info string, so "```javascript showLineNumbers" gives "javascript".
+func fenceLang(line string) string {
+	fields := strings.Fields(strings.TrimLeft(strings.TrimSpace(line), "`~"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// codeFence returns a backtick fence for code: one backtick longer than the
+// longest run of backticks in it, and at least three.
+func codeFence(code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// codeExt returns the file extension used when writing a code block of the
+// given language. JavaScript is the default since most k6 examples are scripts.
+func codeExt(lang string) string {
+	switch strings.ToLower(lang) {
+	case "", "javascript", "js":
+		return ".js"
+	case "typescript", "ts":
+		return ".ts"
+	case "json":
+		return ".json"
+	case "bash", "sh", "shell":
+		return ".sh"
+	case "yaml", "yml":
+		return ".yaml"
+	default:
+		return ".txt"
+	}
+}
+
+// printCode prints only the fenced code blocks of a section, numbered and
+// tagged with their language. A non-empty lang drops the other alternatives
+// of examples that are shown in several languages.
+func printCode(afs fsext.Fs, w io.Writer, idx *Index, section *Section, cacheDir, version, lang string) {
+	content := PreferLang(readAndTransform(afs, idx, cacheDir, section, version), lang)
+	blocks := ExtractCodeBlocks(content)
+
+	if len(blocks) == 0 {
+		_, _ = fmt.Fprintln(w, "(no code examples)")
+		return
+	}
+
+	for i, b := range blocks {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		if b.Lang != "" {
+			_, _ = fmt.Fprintf(w, "Example %d (%s):\n", i+1, b.Lang)
+		} else {
+			_, _ = fmt.Fprintf(w, "Example %d:\n", i+1)
+		}
+		fence := codeFence(b.Code)
+		_, _ = fmt.Fprintf(w, "%s%s\n%s%s\n", fence, b.Lang, b.Code, fence)
+	}
+}
+
+// writeCode writes the fenced code blocks of a section as files under dir,
+// named after the section (e.g. websockets-1.js), and prints their paths.
+// Like printCode, a non-empty lang keeps one alternative per example.
+func writeCode(
+	afs fsext.Fs, w io.Writer, idx *Index, section *Section, dir, cacheDir, version, lang string,
+) error {
+	content := PreferLang(readAndTransform(afs, idx, cacheDir, section, version), lang)
+	blocks := ExtractCodeBlocks(content)
+
+	if len(blocks) == 0 {
+		_, _ = fmt.Fprintln(w, "(no code examples)")
+		return nil
+	}
+
+	if err := afs.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	name := path.Base(section.Slug)
+	for i, b := range blocks {
+		outPath := filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, i+1, codeExt(b.Lang)))
+		if err := fsext.WriteFile(afs, outPath, []byte(b.Code), 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", outPath, err)
+		}
+		_, _ = fmt.Fprintf(w, "Wrote %s\n", outPath)
+	}
+
+	return nil
+}
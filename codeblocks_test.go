@@ -0,0 +1,134 @@
+package docs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    []CodeBlock
+	}{
+		{
+			name:    "no code",
+			content: "# Title\n\nJust prose.\n",
+			want:    nil,
+		},
+		{
+			name:    "language tags",
+			content: "Intro\n\n```javascript\nhttp.get(url);\n```\n\nMiddle\n\n```json\n{}\n```\n",
+			want: []CodeBlock{
+				{Lang: "javascript", Code: "http.get(url);\n"},
+				{Lang: "json", Code: "{}\n"},
+			},
+		},
+		{
+			name:    "untagged and tilde fences",
+			content: "```\nplain\n```\n~~~ bash\nk6 run\n~~~\n",
+			want: []CodeBlock{
+				{Lang: "", Code: "plain\n"},
+				{Lang: "bash", Code: "k6 run\n"},
+			},
+		},
+		{
+			name:    "info string after the language",
+			content: "```javascript showLineNumbers {2}\nhttp.get(url);\n```\n",
+			want:    []CodeBlock{{Lang: "javascript", Code: "http.get(url);\n"}},
+		},
+		{
+			name:    "nested fence",
+			content: "````markdown\n```js\na();\n```\n````\n\n```js\nb();\n```\n",
+			want: []CodeBlock{
+				{Lang: "markdown", Code: "```js\na();\n```\n"},
+				{Lang: "js", Code: "b();\n"},
+			},
+		},
+		{
+			name:    "unterminated fence",
+			content: "```js\na();\nb();",
+			want:    []CodeBlock{{Lang: "js", Code: "a();\nb();\n"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := ExtractCodeBlocks(tt.content)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractCodeBlocks() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("block[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	t.Parallel()
+
+	for code, want := range map[string]string{
+		"a();\n":              "```",
+		"const s = `${a}`;\n": "```",
+		"```js\na();\n```\n":  "````",
+		"````md\n```\n````\n": "`````",
+	} {
+		if got := codeFence(code); got != want {
+			t.Errorf("codeFence(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestCodeCommand(t *testing.T) {
+	t.Parallel()
+
+	run, _ := setupCommand(t)
+
+	t.Run("print", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "code/examples-websockets.txt", run(t, "examples", "websockets", "--code"))
+	})
+	t.Run("no_code", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "code/none.txt", run(t, "using-k6", "--code"))
+	})
+
+	t.Run("write_files", func(t *testing.T) {
+		t.Parallel()
+
+		afs, cacheDir := setupTestdataCache(t)
+		gs := newTestGlobalState(t, afs)
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "examples", "websockets", "--out", "/tmp/out"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+
+		js, err := fsext.ReadFile(afs, filepath.Join("/tmp/out", "websockets-1.js"))
+		if err != nil {
+			t.Fatalf("read websockets-1.js: %v", err)
+		}
+		if !strings.HasPrefix(string(js), "import ws from 'k6/ws';") {
+			t.Errorf("unexpected script content:\n%s", js)
+		}
+		if _, err := fsext.ReadFile(afs, filepath.Join("/tmp/out", "websockets-2.json")); err != nil {
+			t.Errorf("read websockets-2.json: %v", err)
+		}
+		if !strings.Contains(buf.String(), "Wrote "+filepath.Join("/tmp/out", "websockets-1.js")) {
+			t.Errorf("expected written file to be reported, got: %s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package docs
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// reComponentAttr matches a component attribute: name="v", name='v' or
+	// name={"v"}.
+	reComponentAttr = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|'([^']*)'|\{\s*["']([^"']*)["']\s*\})`)
+	// reDefinition matches a "Term\n: definition" pair.
+	reDefinition = regexp.MustCompile(`(?m)^([^\s:<>-][^\n]*)\n:[ \t]+(.+)$`)
+)
+
+// componentLabel returns the first non-empty attribute of names.
+func componentLabel(attrs map[string]string, names ...string) string {
+	for _, n := range names {
+		if v := strings.TrimSpace(attrs[n]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// componentAttrs parses the attributes of a component tag.
+func componentAttrs(s string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range reComponentAttr.FindAllStringSubmatch(s, -1) {
+		attrs[m[1]] = m[2] + m[3] + m[4]
+	}
+	return attrs
+}
+
+// termItems returns the names of the components of a term of a
+// DescriptionList or Glossary.
+func termItems() []string {
+	return []string{"DescriptionListItem", "DescriptionItem", "GlossaryItem", "GlossaryEntry"}
+}
+
+// component writes the component tag toks[i] and returns the index of the
+// last token it used. Known components are rendered as equivalent markdown:
+// terms of a DescriptionList or Glossary become bullets with bold terms,
+// tabs become labelled alternatives like code tabs, and cards become bullets
+// that link the card title to the card's destination. They take the tokens
+// up to their closing tag. Entries without a label, containers such as Tabs
+// and unknown components only lose their tags.
+func (t *transformer) component(w *mdWriter, toks []token, i int) int {
+	tok := &toks[i]
+	if !t.on(stageComponents) {
+		w.prose(t.versioned(tok.raw))
+		return i
+	}
+	attrs := componentAttrs(t.versioned(tok.args))
+	switch {
+	case tok.name == "DescriptionList" || tok.name == "Glossary":
+		// Definitions written as "Term\n: definition" inside a list component.
+		switch {
+		case tok.closing && t.lists > 0:
+			t.lists--
+		case !tok.closing && !tok.selfClosing && closingComponent(toks, i, tok.name) >= 0:
+			t.lists++
+		}
+	case tok.closing:
+		// Closing tags without an opening tag that took them are stripped.
+	case tok.name == "Tab":
+		label := componentLabel(attrs, "label", "title", "name")
+		j := closingComponent(toks, i, "Tab")
+		if label == "" || j < 0 {
+			return i
+		}
+		body := t.writer()
+		t.render(body, toks[i+1:j], nil)
+		w.prose("**Option: " + label + "**\n\n")
+		w.include(body)
+		w.prose("\n")
+		return j
+	case tok.name == "Card":
+		return t.card(w, toks, i, attrs)
+	case slices.Contains(termItems(), tok.name):
+		term := componentLabel(attrs, "term", "name", "title")
+		j := closingComponent(toks, i, termItems()...)
+		if term == "" || j < 0 {
+			return i
+		}
+		body := t.writer()
+		t.render(body, toks[i+1:j], nil)
+		definitionItem(w, term, body)
+		return j
+	}
+	return i
+}
+
+// card writes a self-closing or paired Card component.
+func (t *transformer) card(w *mdWriter, toks []token, i int, attrs map[string]string) int {
+	title := componentLabel(attrs, "title", "name")
+	if title == "" {
+		return i
+	}
+	end := i
+	if !toks[i].selfClosing {
+		if end = closingComponent(toks, i, "Card"); end < 0 {
+			return i
+		}
+	}
+	if href := componentLabel(attrs, "link", "href", "url"); href != "" {
+		title = t.link(title, href)
+	}
+	body := t.writer()
+	if end > i {
+		t.render(body, toks[i+1:end], nil)
+	}
+	if len(body.lines()) == 0 {
+		body.prose(attrs["description"])
+	}
+	definitionItem(w, title, body)
+	return end
+}
+
+// closingComponent returns the index of the first closing tag after
+// toks[i] of a component named one of names, or -1.
+func closingComponent(toks []token, i int, names ...string) int {
+	for j := i + 1; j < len(toks); j++ {
+		if toks[j].kind == tokComponent && toks[j].closing && slices.Contains(names, toks[j].name) {
+			return j
+		}
+	}
+	return -1
+}
+
+// definitionItem writes a term and its definition as a bullet with a bold
+// term. Lines of prose after the first are indented to continue the item.
+func definitionItem(w *mdWriter, term string, definition *mdWriter) {
+	lines := definition.lines()
+	item := "- **" + term + "**"
+	if len(lines) > 0 && lines[0].text != "" {
+		item += ": " + strings.TrimSpace(lines[0].text)
+	}
+	w.prose(item)
+	for _, l := range lines[min(1, len(lines)):] {
+		w.prose("\n")
+		switch text := strings.TrimSpace(l.text); {
+		case l.code:
+			w.code(l.text, true)
+		case text != "":
+			w.prose("  " + text)
+		}
+	}
+}
+
+// definitions renders the "Term\n: definition" pairs of prose in a list
+// component as bullets with bold terms.
+func definitions(s string) string {
+	return reDefinition.ReplaceAllStringFunc(s, func(def string) string {
+		m := reDefinition.FindStringSubmatch(def)
+		return "- **" + strings.TrimSpace(m[1]) + "**: " + m[2]
+	})
+}
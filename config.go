@@ -11,7 +11,21 @@ import (
 
 // docsConfig holds user configuration for the docs subcommand.
 type docsConfig struct {
+	// Renderer is an external command that renders markdown on a TTY.
+	// Empty selects the built-in ANSI renderer; "none" prints raw markdown.
 	Renderer string `yaml:"renderer"`
+	// Pager is the command long TTY output is piped through. Empty falls
+	// back to $PAGER, then "less -R"; "none" disables paging.
+	Pager string `yaml:"pager"`
+	// Lang is the language shown where a code example has alternatives,
+	// unless --lang is given. Empty shows every alternative.
+	Lang string `yaml:"lang"`
+	// Overlays are directories of team-local docs merged into the index.
+	Overlays []overlayConfig `yaml:"overlays"`
+	// Extensions configures where docs for xk6 extensions are found.
+	Extensions extensionsConfig `yaml:"extensions"`
+	// Transforms enables and disables stages of the transform pipeline.
+	Transforms transformsConfig `yaml:"transforms"`
 }
 
 // homeDirFromEnv returns the user's home directory from environment variables.
@@ -0,0 +1,241 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+)
+
+func newDiffCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <from-version> <to-version> [topic...]",
+		Short: "Compare documentation between two k6 versions",
+		Long: "List sections added, removed or renamed between two docs versions and show a unified diff\n" +
+			"of every changed section. Limit the comparison to a topic and its subtopics by naming it.\n" +
+			"Both versions are read from the local cache and downloaded once if missing.",
+		Example: "  k6 x docs diff v1.4.x v1.5.x\n  k6 x docs diff v1.4.x v1.5.x http",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(gs, cmd.OutOrStdout(), opts, args)
+		},
+	}
+}
+
+// sectionPair is a section present in both versions, possibly under a
+// different slug or title.
+type sectionPair struct {
+	from, to         *Section
+	fromText, toText string
+}
+
+// docsDiff is the result of comparing two docs indexes.
+type docsDiff struct {
+	added   []*Section
+	removed []*Section
+	renamed []sectionPair
+	changed []sectionPair
+}
+
+func runDiff(gs *state.GlobalState, w io.Writer, opts *docsOpts, args []string) error {
+	fromVersion, toVersion := args[0], args[1]
+
+	fromDir, fromIdx, err := loadBundle(gs, opts, fromVersion)
+	if err != nil {
+		return err
+	}
+	toDir, toIdx, err := loadBundle(gs, opts, toVersion)
+	if err != nil {
+		return err
+	}
+
+	scope := ""
+	if topic := args[2:]; len(topic) > 0 {
+		exists := func(s string) bool {
+			_, inTo := toIdx.Lookup(s)
+			_, inFrom := fromIdx.Lookup(s)
+			return inTo || inFrom
+		}
+		scope = ResolveWithLookup(topic, exists)
+		if !exists(scope) {
+			return fmt.Errorf("topic not found: %s", strings.Join(topic, " "))
+		}
+	}
+
+	// Both sides are transformed for the same version, so that the
+	// <K6_VERSION> placeholder does not show up as a change everywhere.
+	readFrom := func(sec *Section) string { return readAndTransform(gs.FS, fromIdx, fromDir, sec, toVersion) }
+	readTo := func(sec *Section) string { return readAndTransform(gs.FS, toIdx, toDir, sec, toVersion) }
+
+	d := compareIndexes(fromIdx, toIdx, scope, readFrom, readTo)
+	printDiff(w, d, fromVersion, toVersion)
+	return nil
+}
+
+// loadBundle loads the index of a docs version from the local cache,
+// downloading the bundle first if it is not cached yet. The index gets the
+// transform pipeline selected by the config and opts.
+func loadBundle(gs *state.GlobalState, opts *docsOpts, version string) (string, *Index, error) {
+	dir, err := EnsureDocs(gs.FS, gs.Env, version, http.DefaultClient)
+	if err != nil {
+		return "", nil, fmt.Errorf("ensure docs %s: %w", version, err)
+	}
+	idx, err := LoadIndex(gs.FS, dir)
+	if err != nil {
+		return "", nil, fmt.Errorf("load index %s: %w", version, err)
+	}
+	cfg, _ := loadConfig(gs.FS, gs.Env)
+	if idx.pipeline, err = transformPipeline(cfg, opts); err != nil {
+		return "", nil, err
+	}
+	return dir, idx, nil
+}
+
+// inScope reports whether slug is scope or one of its subtopics. An empty
+// scope matches everything.
+func inScope(slug, scope string) bool {
+	slug, scope = strings.ToLower(slug), strings.ToLower(scope)
+	return scope == "" || slug == scope || strings.HasPrefix(slug, scope+"/")
+}
+
+// scopedSections returns the sections of idx within scope, sorted by slug.
+// Overlay sections are left out: they are not part of any docs version.
+func scopedSections(idx *Index, scope string) []*Section {
+	var secs []*Section
+	for i := range idx.Sections {
+		if inScope(idx.Sections[i].Slug, scope) && !idx.isOverlay(&idx.Sections[i]) {
+			secs = append(secs, &idx.Sections[i])
+		}
+	}
+	sort.Slice(secs, func(i, j int) bool { return secs[i].Slug < secs[j].Slug })
+	return secs
+}
+
+// compareIndexes compares the sections of two indexes within scope, leaving
+// out overlay sections. Sections are matched by slug. A section that keeps its slug but changes its title,
+// or that disappears from one slug while a section with the same title
+// appears under another, is reported as renamed. Matched sections whose
+// transformed content differs are reported as changed.
+func compareIndexes(from, to *Index, scope string, readFrom, readTo func(*Section) string) docsDiff {
+	var (
+		d     docsDiff
+		pairs []sectionPair
+	)
+
+	for _, sec := range scopedSections(from, scope) {
+		if other, ok := to.Lookup(sec.Slug); ok && !to.isOverlay(other) {
+			pairs = append(pairs, sectionPair{from: sec, to: other})
+			if other.Title != sec.Title {
+				d.renamed = append(d.renamed, sectionPair{from: sec, to: other})
+			}
+		} else {
+			d.removed = append(d.removed, sec)
+		}
+	}
+	for _, sec := range scopedSections(to, scope) {
+		if other, ok := from.Lookup(sec.Slug); !ok || from.isOverlay(other) {
+			d.added = append(d.added, sec)
+		}
+	}
+
+	moved := matchMoved(d.removed, d.added)
+	for _, p := range moved {
+		d.removed = removeSection(d.removed, p.from)
+		d.added = removeSection(d.added, p.to)
+	}
+	d.renamed = append(d.renamed, moved...)
+	pairs = append(pairs, moved...)
+
+	for _, p := range pairs {
+		p.fromText, p.toText = readFrom(p.from), readTo(p.to)
+		if p.fromText != p.toText {
+			d.changed = append(d.changed, p)
+		}
+	}
+	sort.SliceStable(d.changed, func(i, j int) bool { return d.changed[i].to.Slug < d.changed[j].to.Slug })
+
+	return d
+}
+
+// matchMoved pairs removed and added sections that share a title. Titles
+// that occur more than once on either side are left unmatched rather than
+// guessed.
+func matchMoved(removed, added []*Section) []sectionPair {
+	count := func(secs []*Section) map[string][]*Section {
+		m := make(map[string][]*Section)
+		for _, s := range secs {
+			m[s.Title] = append(m[s.Title], s)
+		}
+		return m
+	}
+	byTitle := count(added)
+
+	var pairs []sectionPair
+	for title, olds := range count(removed) {
+		if news := byTitle[title]; len(olds) == 1 && len(news) == 1 {
+			pairs = append(pairs, sectionPair{from: olds[0], to: news[0]})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].from.Slug < pairs[j].from.Slug })
+	return pairs
+}
+
+// removeSection returns secs without sec.
+func removeSection(secs []*Section, sec *Section) []*Section {
+	out := secs[:0]
+	for _, s := range secs {
+		if s != sec {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// printDiff writes the summary of d followed by a unified diff of each
+// changed section.
+func printDiff(w io.Writer, d docsDiff, fromVersion, toVersion string) {
+	if len(d.added)+len(d.removed)+len(d.renamed)+len(d.changed) == 0 {
+		_, _ = fmt.Fprintf(w, "No documentation changes between %s and %s.\n", fromVersion, toVersion)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Documentation changes from %s to %s:\n", fromVersion, toVersion)
+
+	list := func(name string, n int, line func(i int) string) {
+		if n == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "\n%s (%d):\n", name, n)
+		for i := range n {
+			_, _ = fmt.Fprintf(w, "  %s\n", line(i))
+		}
+	}
+
+	list("Added", len(d.added), func(i int) string {
+		return fmt.Sprintf("+ %s  %s", d.added[i].Slug, d.added[i].Title)
+	})
+	list("Removed", len(d.removed), func(i int) string {
+		return fmt.Sprintf("- %s  %s", d.removed[i].Slug, d.removed[i].Title)
+	})
+	list("Renamed", len(d.renamed), func(i int) string {
+		p := d.renamed[i]
+		if p.from.Slug == p.to.Slug {
+			return fmt.Sprintf("~ %s  %q -> %q", p.to.Slug, p.from.Title, p.to.Title)
+		}
+		return fmt.Sprintf("~ %s -> %s  %s", p.from.Slug, p.to.Slug, p.to.Title)
+	})
+	list("Changed", len(d.changed), func(i int) string {
+		return fmt.Sprintf("* %s  %s", d.changed[i].to.Slug, d.changed[i].to.Title)
+	})
+
+	for _, p := range d.changed {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprint(w, unifiedDiff(p.fromText, p.toText,
+			path.Join(fromVersion, p.from.Slug), path.Join(toVersion, p.to.Slug)))
+	}
+}
@@ -0,0 +1,180 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	want := "--- a\n+++ b\n" +
+		"@@ -1,5 +1,5 @@\n one\n-two\n+TWO\n three\n four\n five\n" +
+		"@@ -8,3 +8,4 @@\n eight\n nine\n ten\n+eleven\n"
+
+	if got := unifiedDiff(a, b, "a", "b"); got != want {
+		t.Errorf("unifiedDiff mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+	if got := unifiedDiff(a, a, "a", "b"); got != "" {
+		t.Errorf("expected no diff for equal input, got:\n%s", got)
+	}
+	if got := unifiedDiff("", "new\n", "a", "b"); got != "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+new\n" {
+		t.Errorf("unexpected diff from empty input:\n%s", got)
+	}
+}
+
+// setupDiffCaches caches the testdata bundle as v0.55.x and a modified copy
+// as v0.56.x under $HOME, writes the given extra files, and returns a runner
+// for the docs command.
+func setupDiffCaches(t *testing.T, extra map[string]string) func(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	afs, src := setupTestdataCache(t)
+	home := "/home/test"
+
+	for _, version := range []string{"v0.55.x", "v0.56.x"} {
+		dst := filepath.Join(home, ".local", "share", "k6", "docs", version)
+		err := fsext.Walk(afs, src, func(path string, info fs.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, _ := filepath.Rel(src, path)
+			data, err := fsext.ReadFile(afs, path)
+			if err != nil {
+				return err
+			}
+			if err := afs.MkdirAll(filepath.Dir(filepath.Join(dst, rel)), 0o755); err != nil {
+				return err
+			}
+			return fsext.WriteFile(afs, filepath.Join(dst, rel), data, 0o644)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	next := filepath.Join(home, ".local", "share", "k6", "docs", "v0.56.x")
+	var idx Index
+	data, err := fsext.ReadFile(afs, filepath.Join(next, "sections.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatal(err)
+	}
+
+	sections := idx.Sections[:0]
+	for _, sec := range idx.Sections {
+		switch sec.Slug {
+		case "javascript-api/k6-jslib":
+			continue // removed
+		case "using-k6/scenarios":
+			sec.Title = "Scenario configuration" // retitled
+		case "examples/websockets":
+			sec.Slug = "examples/websocket" // moved
+		}
+		sections = append(sections, sec)
+	}
+	sections = append(sections, Section{
+		Slug: "javascript-api/k6-crypto", RelPath: "javascript-api/k6-crypto/_index.md",
+		Title: "k6/crypto", Description: "Cryptographic helpers.", Category: "javascript-api", IsIndex: true,
+	}, Section{
+		Slug: "javascript-api/k6-http/patch", RelPath: "javascript-api/k6-http/patch.md",
+		Title: "patch", Description: "Issue an HTTP PATCH request.", Category: "javascript-api",
+	})
+	idx.Sections = sections
+
+	data, err = json.Marshal(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"sections.json": string(data),
+		"markdown/javascript-api/k6-crypto/_index.md": "# k6/crypto\n\nCryptographic helpers.\n",
+		"markdown/javascript-api/k6-http/patch.md":    "## http.patch(url, [body], [params])\n\nMake an HTTP PATCH request.\n",
+		"markdown/javascript-api/k6-http/get.md": "## http.get(url, [params])\n\n" +
+			"Make an HTTP GET request and return the response.\n\n" +
+			"{{< code >}}\nimport http from 'k6/http';\n\nexport default function () {\n" +
+			"  const res = http.get('https://quickpizza.grafana.com/');\n  console.log(res.status);\n}\n{{< /code >}}\n",
+	}
+	for name, content := range files {
+		if err := fsext.WriteFile(afs, filepath.Join(next, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for path, content := range extra {
+		if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsext.WriteFile(afs, path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = home
+
+	return func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+}
+
+func TestDiffCommand(t *testing.T) {
+	t.Parallel()
+
+	run := setupDiffCaches(t, nil)
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "diff", "v0.55.x", "v0.56.x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "diff/all.txt", out)
+	})
+
+	t.Run("topic", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "diff", "v0.55.x", "v0.56.x", "http")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "diff/http.txt", out)
+	})
+
+	t.Run("no_changes", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "diff", "v0.55.x", "v0.55.x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != "No documentation changes between v0.55.x and v0.55.x.\n" {
+			t.Errorf("unexpected output: %q", out)
+		}
+	})
+
+	t.Run("unknown_topic", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, "diff", "v0.55.x", "v0.56.x", "nonexistent")
+		if err == nil || !strings.Contains(err.Error(), "topic not found: nonexistent") {
+			t.Errorf("expected topic not found error, got %v", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package docs
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -50,10 +51,12 @@ func truncate(s string, limit int) string {
 	return s[:limit-3] + "..."
 }
 
-// listItem is a name+description pair for aligned list rendering.
+// listItem is a name+description pair for aligned list rendering. A
+// non-empty Detail is printed on a line of its own under the description.
 type listItem struct {
 	Name        string
 	Description string
+	Detail      string
 }
 
 // printAlignedList prints items as a left-aligned name+description list.
@@ -74,6 +77,7 @@ func printAlignedList(w io.Writer, items []listItem) {
 	}
 
 	fmtStr := fmt.Sprintf("%s%%-%ds %%s\n", indent, maxWidth+1)
+	detailFmt := fmt.Sprintf("%s%%-%ds %%s\n", strings.Repeat(" ", len(indent)), maxWidth+1)
 
 	// Reset seen for the printing pass.
 	for k := range seen {
@@ -85,10 +89,14 @@ func printAlignedList(w io.Writer, items []listItem) {
 		}
 		seen[item.Name] = true
 		_, _ = fmt.Fprintf(w, fmtStr, item.Name, truncate(item.Description, 80))
+		if item.Detail != "" {
+			_, _ = fmt.Fprintf(w, detailFmt, "", item.Detail)
+		}
 	}
 }
 
-// printTOC prints the table of contents grouped by category.
+// printTOC prints the table of contents grouped by category. Categories
+// left without topics by the filter of the index are skipped.
 func printTOC(w io.Writer, idx *Index, version string) {
 	_, _ = fmt.Fprintf(w, "k6 Documentation (%s)\n", version)
 	_, _ = fmt.Fprintln(w, "Use: k6 x docs <topic>")
@@ -96,12 +104,17 @@ func printTOC(w io.Writer, idx *Index, version string) {
 	topLevel := idx.TopLevel()
 
 	for _, cat := range topLevel {
+		all := idx.Children(cat.Slug)
+		children := idx.listed(all)
+		if len(children) == 0 && (len(all) > 0 || !idx.filter.match(cat)) {
+			continue
+		}
+
 		_, _ = fmt.Fprintf(w, "\n## %s\n", cat.Title)
 
-		children := idx.Children(cat.Slug)
 		if len(children) == 0 {
 			// Show the category itself if it has no children.
-			_, _ = fmt.Fprintf(w, "- %s %s\n", childName(cat.Slug, ""), truncate(cat.Description, 80))
+			_, _ = fmt.Fprintf(w, "- %s %s\n", childName(cat.Slug, ""), truncate(badges(cat)+cat.Description, 80))
 			continue
 		}
 
@@ -109,7 +122,7 @@ func printTOC(w io.Writer, idx *Index, version string) {
 		for _, child := range children {
 			items = append(items, listItem{
 				Name:        childName(child.Slug, cat.Slug),
-				Description: child.Description,
+				Description: badges(child) + child.Description,
 			})
 		}
 		printAlignedList(w, items)
@@ -118,16 +131,15 @@ func printTOC(w io.Writer, idx *Index, version string) {
 }
 
 // printSection prints a section's markdown content, read from the cache dir.
-// If the section has children, a subtopics footer is appended.
-func printSection(afs fsext.Fs, w io.Writer, idx *Index, section *Section, cacheDir, version string) {
-	content := readAndTransform(afs, cacheDir, section.RelPath, version)
-	if content != "" {
-		_, _ = fmt.Fprint(w, content)
-		if !strings.HasSuffix(content, "\n") {
-			_, _ = fmt.Fprintln(w)
-		}
-	}
-
+// The user's notes, if any, follow in a "Your notes" block, and if the
+// section has children, a subtopics footer is appended. A positive budget
+// trims the content to roughly that many tokens, less what the footnotes,
+// notes and footer take; those are never trimmed.
+func printSection(
+	afs fsext.Fs, w io.Writer, idx *Index, section *Section, notes, cacheDir, version string, budget int,
+) {
+	var footer strings.Builder
+	printNotes(&footer, idx, notes, section.Slug)
 	children := idx.Children(section.Slug)
 	if len(children) > 0 {
 		names := make([]string, 0, len(children))
@@ -135,11 +147,25 @@ func printSection(afs fsext.Fs, w io.Writer, idx *Index, section *Section, cache
 			names = append(names, childName(c.Slug, section.Slug))
 		}
 
-		_, _ = fmt.Fprintln(w)
-		_, _ = fmt.Fprintln(w, "---")
-		_, _ = fmt.Fprintf(w, "Subtopics: %s\n", strings.Join(names, ", "))
-		_, _ = fmt.Fprintf(w, "Use: k6 x docs %s <subtopic>\n", slugToArgs(section.Slug))
+		_, _ = fmt.Fprintln(&footer)
+		_, _ = fmt.Fprintln(&footer, "---")
+		_, _ = fmt.Fprintf(&footer, "Subtopics: %s\n", strings.Join(names, ", "))
+		_, _ = fmt.Fprintf(&footer, "Use: k6 x docs %s <subtopic>\n", slugToArgs(section.Slug))
+	}
+
+	content, footnotes := cutFootnotes(readAndTransform(afs, idx, cacheDir, section, version))
+	if budget > 0 {
+		budget = max(budget-approxTokens(footnotes)-approxTokens(footer.String()), minSectionBudget)
 	}
+	content = fitBudget(content, budget, "k6 x docs "+commandArgs(idx, section.Slug))
+	content = appendFootnotes(content, footnotes)
+	if content != "" {
+		_, _ = fmt.Fprint(w, content)
+		if !strings.HasSuffix(content, "\n") {
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+	_, _ = fmt.Fprint(w, footer.String())
 }
 
 // printList prints children of a section in compact format.
@@ -150,11 +176,11 @@ func printList(w io.Writer, idx *Index, slug string) {
 		return
 	}
 
-	children := idx.Children(slug)
+	children := idx.listed(idx.Children(slug))
 
 	_, _ = fmt.Fprintf(w, "%s", sec.Title)
-	if sec.Description != "" {
-		_, _ = fmt.Fprintf(w, " — %s", sec.Description)
+	if desc := badges(sec) + sec.Description; desc != "" {
+		_, _ = fmt.Fprintf(w, " — %s", strings.TrimSpace(desc))
 	}
 	_, _ = fmt.Fprintln(w)
 
@@ -167,7 +193,8 @@ func printList(w io.Writer, idx *Index, slug string) {
 	for _, child := range children {
 		items = append(items, listItem{
 			Name:        childName(child.Slug, slug),
-			Description: child.Description,
+			Description: badges(child) + child.Description,
+			Detail:      signatureLine(child),
 		})
 	}
 	printAlignedList(w, items)
@@ -198,16 +225,24 @@ func searchGroupKey(slug string) string {
 }
 
 // printSearch prints search results grouped hierarchically by topic.
-func printSearch(afs fsext.Fs, w io.Writer, idx *Index, term, cacheDir, version string) {
+// A positive budget keeps only as many result lines as fit in it.
+func printSearch(afs fsext.Fs, w io.Writer, idx *Index, term, cacheDir, version string, budget int) {
+	if budget > 0 {
+		var buf bytes.Buffer
+		printSearch(afs, &buf, idx, term, cacheDir, version, 0)
+		_, _ = fmt.Fprint(w, fitLines(buf.String(), budget, "[More results omitted. Narrow the search term to see them.]"))
+		return
+	}
+
 	readContent := func(slug string) string {
 		sec, ok := idx.Lookup(slug)
 		if !ok {
 			return ""
 		}
-		return readAndTransform(afs, cacheDir, sec.RelPath, version)
+		return readAndTransform(afs, idx, cacheDir, sec, version)
 	}
 
-	results := idx.Search(term, readContent)
+	results := idx.listed(idx.Search(term, readContent))
 
 	_, _ = fmt.Fprintf(w, "Results for %q:\n", term)
 
@@ -262,7 +297,7 @@ func printSearch(afs fsext.Fs, w io.Writer, idx *Index, term, cacheDir, version
 
 		// Print group header.
 		if groupSec != nil {
-			_, _ = fmt.Fprintf(w, "%s: %s\n", key, truncate(groupSec.Description, 80))
+			_, _ = fmt.Fprintf(w, "%s: %s\n", key, truncate(badges(groupSec)+groupSec.Description, 80))
 		} else {
 			_, _ = fmt.Fprintf(w, "%s:\n", key)
 		}
@@ -275,7 +310,7 @@ func printSearch(afs fsext.Fs, w io.Writer, idx *Index, term, cacheDir, version
 			}
 			items = append(items, listItem{
 				Name:        childName(sec.Slug, groupSlug),
-				Description: sec.Description,
+				Description: badges(sec) + sec.Description,
 			})
 		}
 		printAlignedList(w, items)
@@ -285,13 +320,13 @@ func printSearch(afs fsext.Fs, w io.Writer, idx *Index, term, cacheDir, version
 }
 
 // printBestPractices reads and prints the best_practices.md file from the cache.
-func printBestPractices(afs fsext.Fs, w io.Writer, cacheDir, version string) error {
+func printBestPractices(afs fsext.Fs, w io.Writer, idx *Index, cacheDir, version string) error {
 	path := filepath.Join(cacheDir, "best_practices.md")
 	data, err := fsext.ReadFile(afs, path)
 	if err != nil {
 		return fmt.Errorf("read best practices: %w", err)
 	}
-	content := Transform(string(data), version)
+	content := idx.transform(string(data), version)
 	_, _ = fmt.Fprint(w, content)
 	if !strings.HasSuffix(content, "\n") {
 		_, _ = fmt.Fprintln(w)
@@ -299,16 +334,40 @@ func printBestPractices(afs fsext.Fs, w io.Writer, cacheDir, version string) err
 	return nil
 }
 
-// printAll prints all sections sequentially.
-func printAll(afs fsext.Fs, w io.Writer, idx *Index, cacheDir, version string) {
+// printAll prints all sections sequentially. A positive budget is spread
+// over the sections still to be printed, so that each section gets an equal
+// share of what the previous ones left unused.
+func printAll(afs fsext.Fs, w io.Writer, idx *Index, cacheDir, version string, budget int) {
 	_, _ = fmt.Fprintf(w, "k6 Documentation (%s)\n", version)
 
+	// left counts the sections with content still to print, which share
+	// the remaining budget. Sections without content are never printed, so
+	// they are not counted as omitted either.
+	contents := make([]string, len(idx.Sections))
+	remaining, left := budget, 0
 	for i := range idx.Sections {
-		sec := &idx.Sections[i]
-		content := readAndTransform(afs, cacheDir, sec.RelPath, version)
+		contents[i] = readAndTransform(afs, idx, cacheDir, &idx.Sections[i], version)
+		if contents[i] != "" {
+			left++
+		}
+	}
+	for i, content := range contents {
 		if content == "" {
 			continue
 		}
+		if budget > 0 {
+			if remaining < minSectionBudget {
+				_, _ = fmt.Fprintf(w, "[%d more sections omitted. List topics: k6 x docs --list]\n", left)
+				return
+			}
+			share := max(minSectionBudget, remaining/max(left, 1))
+			body, footnotes := cutFootnotes(content)
+			share = max(share-approxTokens(footnotes), minSectionBudget)
+			fullCmd := "k6 x docs " + commandArgs(idx, idx.Sections[i].Slug)
+			content = appendFootnotes(fitBudget(body, share, fullCmd), footnotes)
+			remaining -= approxTokens(content)
+			left--
+		}
 		_, _ = fmt.Fprint(w, content)
 		if !strings.HasSuffix(content, "\n") {
 			_, _ = fmt.Fprintln(w)
@@ -317,9 +376,13 @@ func printAll(afs fsext.Fs, w io.Writer, idx *Index, cacheDir, version string) {
 	}
 }
 
-// readMarkdown reads a markdown file from the cache directory.
-func readMarkdown(afs fsext.Fs, cacheDir, relPath string) string {
-	path := filepath.Join(cacheDir, "markdown", relPath)
+// readMarkdown reads a section's markdown file from the cache directory, or
+// from its own directory for overlay sections.
+func readMarkdown(afs fsext.Fs, cacheDir string, sec *Section) string {
+	path := filepath.Join(cacheDir, "markdown", sec.RelPath)
+	if sec.dir != "" {
+		path = filepath.Join(sec.dir, sec.RelPath)
+	}
 	data, err := fsext.ReadFile(afs, path)
 	if err != nil {
 		return ""
@@ -327,11 +390,12 @@ func readMarkdown(afs fsext.Fs, cacheDir, relPath string) string {
 	return string(data)
 }
 
-// readAndTransform reads a markdown file and applies runtime transforms.
-func readAndTransform(afs fsext.Fs, cacheDir, relPath, version string) string {
-	raw := readMarkdown(afs, cacheDir, relPath)
+// readAndTransform reads a section's markdown file and applies the runtime
+// transforms of idx.
+func readAndTransform(afs fsext.Fs, idx *Index, cacheDir string, sec *Section, version string) string {
+	raw := readMarkdown(afs, cacheDir, sec)
 	if raw == "" {
 		return ""
 	}
-	return Transform(raw, version)
+	return idx.transform(raw, version)
 }
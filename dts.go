@@ -0,0 +1,365 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+var (
+	// reModuleName matches the title of a k6 module page, e.g. "k6/http".
+	reModuleName = regexp.MustCompile(`^k6(?:/[a-z0-9-]+)*$`)
+	// reIdent matches a JavaScript identifier.
+	reIdent = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+	// reTypeSeparator splits the alternatives of a documented type, as in
+	// "string / HTTP URL" or "number | null".
+	reTypeSeparator = regexp.MustCompile(`\s+(?:/|\||or)\s+`)
+	// reGenericType matches a generic type such as Promise<Response>.
+	// Captures: [1]=name, [2]=argument
+	reGenericType = regexp.MustCompile(`^(Array|Promise|Set)\s*<\s*(.+?)\s*>$`)
+)
+
+// tsPrimitive returns the TypeScript type for a documented primitive type
+// name, or "" if name is not one.
+func tsPrimitive(name string) string {
+	switch strings.ToLower(name) {
+	case "string", "boolean", "object", "any", "null", "undefined", "void", "number":
+		return strings.ToLower(name)
+	case "int", "integer", "float":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "array":
+		return "any[]"
+	case "function":
+		return "(...args: any[]) => any"
+	}
+	return ""
+}
+
+// isTSBuiltin reports whether TypeScript declares the type name itself.
+func isTSBuiltin(name string) bool {
+	switch name {
+	case "Array", "ArrayBuffer", "Date", "Error", "Map", "Object", "Promise", "Set", "Uint8Array":
+		return true
+	}
+	return false
+}
+
+// dtsFunc is a documented function or method.
+type dtsFunc struct {
+	name string
+	sig  *Signature
+	doc  string
+}
+
+// dtsClass groups the documented methods and constructor of a class.
+type dtsClass struct {
+	name    string
+	doc     string
+	ctor    *dtsFunc
+	methods []dtsFunc
+}
+
+// dtsModule holds the declarations of a k6 module.
+type dtsModule struct {
+	name      string
+	functions []dtsFunc
+	classes   []*dtsClass
+	// types are the names of the types referenced by the declarations.
+	types map[string]bool
+}
+
+// class returns the class named name, adding it if needed. doc describes
+// the class unless it already has a description.
+func (m *dtsModule) class(name, doc string) *dtsClass {
+	for _, c := range m.classes {
+		if c.name == name {
+			if c.doc == "" {
+				c.doc = doc
+			}
+			return c
+		}
+	}
+	c := &dtsClass{name: name, doc: doc}
+	m.classes = append(m.classes, c)
+	return c
+}
+
+// moduleOf returns the nearest ancestor of sec, or sec itself, that is a
+// module page, and the parent of sec.
+func moduleOf(idx *Index, sec *Section) (*Section, *Section) {
+	parent, _ := parentSection(idx, sec.Slug)
+	for p := sec; p != nil; {
+		if reModuleName.MatchString(p.Title) {
+			return p, parent
+		}
+		p, _ = parentSection(idx, p.Slug)
+	}
+	return nil, parent
+}
+
+// funcName returns the name of a documented call: "http.get(url)" is get,
+// and "new Client()" is Client.
+func funcName(call string) (string, bool) {
+	name, _, _ := strings.Cut(call, "(")
+	name, ctor := strings.CutPrefix(strings.TrimSpace(name), "new ")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	return name, ctor
+}
+
+// buildDTS collects the documented functions of the JavaScript API by
+// module. Functions directly under a module page are exported by the module;
+// functions under another page, such as a class, are methods of the class
+// named by the page title.
+func buildDTS(idx *Index) []*dtsModule {
+	var modules []*dtsModule
+	byName := make(map[string]*dtsModule)
+
+	idx.Walk(func(sec *Section, _ int) {
+		if sec.Category != "javascript-api" || sec.Signature == nil {
+			return
+		}
+		modSec, parent := moduleOf(idx, sec)
+		name, ctor := funcName(sec.Signature.Name)
+		if modSec == nil || parent == nil || !reIdent.MatchString(name) {
+			return
+		}
+
+		mod, ok := byName[modSec.Title]
+		if !ok {
+			mod = &dtsModule{name: modSec.Title, types: make(map[string]bool)}
+			byName[mod.name] = mod
+			modules = append(modules, mod)
+		}
+
+		fn := dtsFunc{name: name, sig: sec.Signature, doc: sec.Description}
+		switch {
+		case ctor && parent.Title == name:
+			// The constructor is documented on a page of its class.
+			mod.class(name, parent.Description).ctor = &fn
+		case ctor:
+			mod.class(name, sec.Description).ctor = &fn
+		case parent == modSec:
+			mod.functions = append(mod.functions, fn)
+		case reIdent.MatchString(parent.Title):
+			c := mod.class(parent.Title, parent.Description)
+			c.methods = append(c.methods, fn)
+		}
+	})
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].name < modules[j].name })
+	return modules
+}
+
+// tsType converts a documented type to TypeScript, recording the names of
+// referenced types in types. Types it cannot read become any.
+func tsType(s string, types map[string]bool) string {
+	s = strings.TrimSpace(s)
+	if alts := reTypeSeparator.Split(s, -1); len(alts) > 1 {
+		out := make([]string, 0, len(alts))
+		for _, a := range alts {
+			t := tsType(a, types)
+			if strings.Contains(t, "=>") {
+				t = "(" + t + ")"
+			}
+			if !slices.Contains(out, t) {
+				out = append(out, t)
+			}
+		}
+		if slices.Contains(out, "any") {
+			return "any"
+		}
+		return strings.Join(out, " | ")
+	}
+
+	if t := tsPrimitive(s); t != "" {
+		return t
+	}
+	if elem, ok := strings.CutSuffix(s, "[]"); ok {
+		if t := tsType(elem, types); t != "any" && !strings.Contains(t, " ") {
+			return t + "[]"
+		}
+		return "any[]"
+	}
+	if m := reGenericType.FindStringSubmatch(s); m != nil {
+		return m[1] + "<" + tsType(m[2], types) + ">"
+	}
+	if reIdent.MatchString(s) && s[0] >= 'A' && s[0] <= 'Z' {
+		types[s] = true
+		return s
+	}
+	return "any"
+}
+
+// tsParams renders the parameters of sig. Parameters after an optional one
+// are optional too, and documented sub-properties such as "params.tags" are
+// left out.
+func tsParams(sig *Signature, types map[string]bool) string {
+	var (
+		out      []string
+		optional bool
+	)
+	for _, p := range sig.Params {
+		name, rest := strings.CutPrefix(p.Name, "...")
+		if !reIdent.MatchString(name) {
+			continue
+		}
+		typ := tsType(p.Type, types)
+		switch {
+		case rest:
+			if !strings.HasSuffix(typ, "[]") {
+				typ = "any[]"
+			}
+			out = append(out, "..."+name+": "+typ)
+		default:
+			optional = optional || p.Optional
+			if optional {
+				name += "?"
+			}
+			out = append(out, name+": "+typ)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// tsReturn renders the return type of sig.
+func tsReturn(sig *Signature, types map[string]bool) string {
+	if r := strings.TrimSpace(sig.Returns); r != "" && r != "-" {
+		return tsType(r, types)
+	}
+	return "void"
+}
+
+// writeDocComment writes a JSDoc comment with the description of a
+// declaration and its parameters.
+func writeDocComment(sb *strings.Builder, indent, doc string, sig *Signature) {
+	escape := strings.NewReplacer("*/", "*\\/").Replace
+	var lines []string
+	if doc != "" {
+		lines = append(lines, escape(doc))
+	}
+	if sig != nil {
+		for _, p := range sig.Params {
+			if reIdent.MatchString(strings.TrimPrefix(p.Name, "...")) && p.Description != "" {
+				lines = append(lines, "@param "+strings.TrimPrefix(p.Name, "...")+" "+escape(p.Description))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sb.WriteString(indent + "/**\n")
+	for _, l := range lines {
+		sb.WriteString(indent + " * " + l + "\n")
+	}
+	sb.WriteString(indent + " */\n")
+}
+
+// moduleDTS renders the declarations of a module.
+func moduleDTS(mod *dtsModule, version string) string {
+	var body strings.Builder
+	for _, fn := range mod.functions {
+		writeDocComment(&body, "  ", fn.doc, fn.sig)
+		_, _ = fmt.Fprintf(&body, "  export function %s(%s): %s;\n",
+			fn.name, tsParams(fn.sig, mod.types), tsReturn(fn.sig, mod.types))
+	}
+
+	declared := make(map[string]bool)
+	for _, c := range mod.classes {
+		declared[c.name] = true
+		if c.ctor != nil {
+			writeDocComment(&body, "  ", c.doc, nil)
+			_, _ = fmt.Fprintf(&body, "  export class %s {\n", c.name)
+			writeDocComment(&body, "    ", "", c.ctor.sig)
+			_, _ = fmt.Fprintf(&body, "    constructor(%s);\n  }\n", tsParams(c.ctor.sig, mod.types))
+		}
+		if len(c.methods) == 0 {
+			continue
+		}
+		if c.ctor == nil {
+			writeDocComment(&body, "  ", c.doc, nil)
+		}
+		_, _ = fmt.Fprintf(&body, "  export interface %s {\n", c.name)
+		for _, fn := range c.methods {
+			writeDocComment(&body, "    ", fn.doc, fn.sig)
+			_, _ = fmt.Fprintf(&body, "    %s(%s): %s;\n", fn.name, tsParams(fn.sig, mod.types), tsReturn(fn.sig, mod.types))
+		}
+		body.WriteString("  }\n")
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "// Type definitions for %s, k6 %s.\n", mod.name, version)
+	sb.WriteString("// Generated from the k6 documentation by: k6 x docs export dts\n\n")
+	_, _ = fmt.Fprintf(&sb, "declare module %q {\n", mod.name)
+
+	// Types that are documented on pages without methods are left opaque.
+	var opaque []string
+	for t := range mod.types {
+		if !declared[t] && !isTSBuiltin(t) {
+			opaque = append(opaque, t)
+		}
+	}
+	sort.Strings(opaque)
+	for _, t := range opaque {
+		_, _ = fmt.Fprintf(&sb, "  export type %s = any;\n", t)
+	}
+	if len(opaque) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(body.String())
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dtsFile returns the file name of a module's declarations, e.g.
+// k6-http.d.ts.
+func dtsFile(module string) string {
+	return strings.ReplaceAll(module, "/", "-") + ".d.ts"
+}
+
+// writeDTS writes a TypeScript declaration file per k6 module, built from
+// the signatures in the index, and an index.d.ts that references them all.
+func writeDTS(afs fsext.Fs, w io.Writer, idx *Index, dir, _, version string) error {
+	modules := buildDTS(idx)
+	if len(modules) == 0 {
+		return fmt.Errorf("the docs for %s have no function signatures", version)
+	}
+	if err := afs.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	write := func(name, content string) error {
+		p := filepath.Join(dir, name)
+		if err := fsext.WriteFile(afs, p, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", p, err)
+		}
+		return nil
+	}
+
+	var index strings.Builder
+	_, _ = fmt.Fprintf(&index, "// Type definitions for k6 %s.\n", version)
+	index.WriteString("// Generated from the k6 documentation by: k6 x docs export dts\n\n")
+	for _, mod := range modules {
+		if err := write(dtsFile(mod.name), moduleDTS(mod, version)); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(&index, "/// <reference path=%q />\n", dtsFile(mod.name))
+	}
+	if err := write("index.d.ts", index.String()); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "Wrote %d declaration files to %s\n", len(modules)+1, dir)
+	return nil
+}
@@ -0,0 +1,117 @@
+package docs
+
+import (
+	"testing"
+)
+
+func TestTSType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		doc   string
+		want  string
+		types []string
+	}{
+		{doc: "string", want: "string"},
+		{doc: "Integer", want: "number"},
+		{doc: "string / object", want: "string | object"},
+		{doc: "string / HTTP URL", want: "any"},
+		{doc: "Response", want: "Response", types: []string{"Response"}},
+		{doc: "Promise<Response>", want: "Promise<Response>", types: []string{"Response"}},
+		{doc: "string[]", want: "string[]"},
+		{doc: "function | null", want: "((...args: any[]) => any) | null"},
+		{doc: "a map of headers", want: "any"},
+	}
+
+	for _, tt := range tests {
+		types := make(map[string]bool)
+		if got := tsType(tt.doc, types); got != tt.want {
+			t.Errorf("tsType(%q) = %q, want %q", tt.doc, got, tt.want)
+		}
+		if len(types) != len(tt.types) {
+			t.Errorf("tsType(%q) types = %v, want %v", tt.doc, types, tt.types)
+		}
+		for _, name := range tt.types {
+			if !types[name] {
+				t.Errorf("tsType(%q) types = %v, want %v", tt.doc, types, tt.types)
+			}
+		}
+	}
+}
+
+func TestModuleDTS(t *testing.T) {
+	t.Parallel()
+
+	sections := []Section{
+		{Slug: "javascript-api", Title: "JavaScript API", Category: "javascript-api", IsIndex: true},
+		{Slug: "javascript-api/k6-net-grpc", Title: "k6/net/grpc", Category: "javascript-api", IsIndex: true},
+		{
+			Slug: "javascript-api/k6-net-grpc/client", Title: "Client", Description: "gRPC client.",
+			Category: "javascript-api", IsIndex: true, Weight: 1,
+		},
+		{
+			Slug: "javascript-api/k6-net-grpc/client/new", Title: "new Client()", Category: "javascript-api",
+			Signature: &Signature{Name: "new Client()"},
+		},
+		{
+			Slug: "javascript-api/k6-net-grpc/client/invoke", Title: "Client.invoke", Category: "javascript-api",
+			Description: "Invokes a unary RPC.", Weight: 1,
+			Signature: &Signature{
+				Name: "Client.invoke(url, request, [params])",
+				Params: []Param{
+					{Name: "url", Type: "string", Description: "The method to call."},
+					{Name: "request", Type: "object", Description: "The request message."},
+					{Name: "params", Type: "object", Optional: true},
+					{Name: "params.tags", Type: "object"},
+				},
+				Returns: "Response",
+			},
+		},
+		{
+			Slug: "javascript-api/k6-net-grpc/client/close", Title: "Client.close", Category: "javascript-api",
+			Description: "Closes the */ connection.", Weight: 2,
+			Signature: &Signature{Name: "Client.close()", Returns: "-"},
+		},
+		{
+			Slug: "javascript-api/k6-net-grpc/stream", Title: "Stream", Category: "javascript-api", Weight: 2,
+		},
+	}
+	PopulateChildren(sections)
+	idx := &Index{Sections: sections}
+	idx.reindex()
+
+	modules := buildDTS(idx)
+	if len(modules) != 1 || modules[0].name != "k6/net/grpc" {
+		t.Fatalf("modules = %+v", modules)
+	}
+
+	want := `// Type definitions for k6/net/grpc, k6 v1.0.0.
+// Generated from the k6 documentation by: k6 x docs export dts
+
+declare module "k6/net/grpc" {
+  export type Response = any;
+
+  /**
+   * gRPC client.
+   */
+  export class Client {
+    constructor();
+  }
+  export interface Client {
+    /**
+     * Invokes a unary RPC.
+     * @param url The method to call.
+     * @param request The request message.
+     */
+    invoke(url: string, request: object, params?: object): Response;
+    /**
+     * Closes the *\/ connection.
+     */
+    close(): void;
+  }
+}
+`
+	if got := moduleDTS(modules[0], "v1.0.0"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// exportOpts holds the flags shared by the export subcommands.
+type exportOpts struct {
+	out  string
+	full bool
+}
+
+func newExportCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	var eopts exportOpts
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export documentation in other formats",
+		Args:  cobra.NoArgs,
+	}
+	exportCmd.PersistentFlags().StringVar(&eopts.out, "out", "", "Output directory")
+
+	llmsCmd := &cobra.Command{
+		Use:   "llms",
+		Short: "Export an llms.txt index and llms-full.txt content",
+		Long: "Print llms.txt to stdout (or llms-full.txt with --full).\n" +
+			"With --out, write both llms.txt and llms-full.txt to the directory.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExportLLMs(gs, cmd, opts, &eopts)
+		},
+	}
+	llmsCmd.Flags().BoolVar(&eopts.full, "full", false, "Print llms-full.txt instead of llms.txt")
+	exportCmd.AddCommand(llmsCmd)
+
+	exportCmd.AddCommand(&cobra.Command{
+		Use:   "man",
+		Short: "Export a man page per section",
+		Long: "Write a roff man page per section, e.g. k6-docs-http-get(7), into <out>/man7.\n" +
+			"Read them with: MANPATH=<out> man k6-docs-http-get",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExportSite(gs, cmd, opts, &eopts, writeMan)
+		},
+	})
+	exportCmd.AddCommand(&cobra.Command{
+		Use:   "html",
+		Short: "Export a static, linked HTML site",
+		Long:  "Write an HTML page per section, linked by the docs tree, into <out>. Open <out>/index.html to browse.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExportSite(gs, cmd, opts, &eopts, writeHTML)
+		},
+	})
+
+	exportCmd.AddCommand(&cobra.Command{
+		Use:   "dts",
+		Short: "Export TypeScript declarations of the JavaScript API",
+		Long: "Write a .d.ts file per k6 module, built from the documented function signatures of this docs\n" +
+			"version, and an index.d.ts that references them, into <out>.",
+		Example: "  k6 x docs export dts --out types/",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExportSite(gs, cmd, opts, &eopts, writeDTS)
+		},
+	})
+
+	return exportCmd
+}
+
+func runExportLLMs(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, eopts *exportOpts) error {
+	version, cacheDir, idx, err := setup(gs, opts)
+	if err != nil {
+		return err
+	}
+
+	index := func(w io.Writer) { printLLMs(w, idx, version) }
+	full := func(w io.Writer) { printLLMsFull(gs.FS, w, idx, cacheDir, version) }
+
+	if eopts.out == "" {
+		if eopts.full {
+			full(cmd.OutOrStdout())
+		} else {
+			index(cmd.OutOrStdout())
+		}
+		return nil
+	}
+
+	if err := exportFile(gs.FS, cmd.OutOrStdout(), eopts.out, "llms.txt", index); err != nil {
+		return err
+	}
+	return exportFile(gs.FS, cmd.OutOrStdout(), eopts.out, "llms-full.txt", full)
+}
+
+// siteWriter writes a multi-file export of the whole index into a directory.
+type siteWriter func(afs fsext.Fs, w io.Writer, idx *Index, dir, cacheDir, version string) error
+
+// runExportSite runs an export that writes many files and so requires --out.
+func runExportSite(
+	gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, eopts *exportOpts, write siteWriter,
+) error {
+	if eopts.out == "" {
+		return fmt.Errorf("%s requires --out", cmd.CommandPath())
+	}
+
+	version, cacheDir, idx, err := setup(gs, opts)
+	if err != nil {
+		return err
+	}
+
+	return write(gs.FS, cmd.OutOrStdout(), idx, eopts.out, cacheDir, version)
+}
+
+// exportFile renders a file with write into dir/name and reports the path on w.
+func exportFile(afs fsext.Fs, w io.Writer, dir, name string, write func(io.Writer)) error {
+	if err := afs.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	var buf bytes.Buffer
+	write(&buf)
+
+	outPath := filepath.Join(dir, name)
+	if err := fsext.WriteFile(afs, outPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", outPath, err)
+	}
+	_, _ = fmt.Fprintf(w, "Wrote %s\n", outPath)
+	return nil
+}
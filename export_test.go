@@ -0,0 +1,185 @@
+package docs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestExportLLMs(t *testing.T) {
+	t.Parallel()
+
+	run, _ := setupCommand(t)
+
+	t.Run("index", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "export/llms.txt", run(t, "export", "llms"))
+	})
+	t.Run("full", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "export/llms-full.txt", run(t, "export", "llms", "--full"))
+	})
+
+	t.Run("out_dir", func(t *testing.T) {
+		t.Parallel()
+
+		afs, cacheDir := setupTestdataCache(t)
+		gs := newTestGlobalState(t, afs)
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "export", "llms", "--out", "/tmp/llms"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+
+		for _, name := range []string{"llms.txt", "llms-full.txt"} {
+			data, err := fsext.ReadFile(afs, filepath.Join("/tmp/llms", name))
+			if err != nil {
+				t.Fatalf("read %s: %v", name, err)
+			}
+			if !strings.HasPrefix(string(data), "# k6 Documentation (v0.55.x)") {
+				t.Errorf("%s: unexpected header:\n%s", name, data)
+			}
+			if !strings.Contains(buf.String(), "Wrote "+filepath.Join("/tmp/llms", name)) {
+				t.Errorf("expected %s to be reported, got: %s", name, buf.String())
+			}
+		}
+	})
+}
+
+func TestExportSite(t *testing.T) {
+	t.Parallel()
+
+	export := func(t *testing.T, format string) (fsext.Fs, string) {
+		t.Helper()
+		afs, cacheDir := setupTestdataCache(t)
+		gs := newTestGlobalState(t, afs)
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "export", format, "--out", "/tmp/site"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+		return afs, buf.String()
+	}
+
+	read := func(t *testing.T, afs fsext.Fs, name string) string {
+		t.Helper()
+		data, err := fsext.ReadFile(afs, filepath.Join("/tmp/site", name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(data)
+	}
+
+	t.Run("man", func(t *testing.T) {
+		t.Parallel()
+		afs, out := export(t, "man")
+		if !strings.HasPrefix(out, "Wrote ") || !strings.Contains(out, "man pages to /tmp/site/man7") {
+			t.Errorf("unexpected report: %s", out)
+		}
+		assertGolden(t, "export/k6-docs.7", read(t, afs, "man7/k6-docs.7"))
+		assertGolden(t, "export/k6-docs-using-k6-scenarios.7", read(t, afs, "man7/k6-docs-using-k6-scenarios.7"))
+		assertGolden(t, "export/k6-docs-http-get.7", read(t, afs, "man7/k6-docs-http-get.7"))
+	})
+
+	t.Run("html", func(t *testing.T) {
+		t.Parallel()
+		afs, out := export(t, "html")
+		if !strings.Contains(out, "HTML pages to /tmp/site") {
+			t.Errorf("unexpected report: %s", out)
+		}
+		assertGolden(t, "export/index.html", read(t, afs, "index.html"))
+		assertGolden(t, "export/scenarios.html", read(t, afs, "using-k6/scenarios/index.html"))
+		read(t, afs, "style.css")
+	})
+
+	t.Run("dts", func(t *testing.T) {
+		t.Parallel()
+		afs, out := export(t, "dts")
+		if !strings.Contains(out, "Wrote 2 declaration files to /tmp/site") {
+			t.Errorf("unexpected report: %s", out)
+		}
+		assertGolden(t, "export/index.d.ts", read(t, afs, "index.d.ts"))
+		assertGolden(t, "export/k6-http.d.ts", read(t, afs, "k6-http.d.ts"))
+	})
+
+	t.Run("requires_out", func(t *testing.T) {
+		t.Parallel()
+		_, runErr := setupCommand(t)
+		for _, format := range []string{"man", "html", "dts"} {
+			err := runErr(t, "export", format)
+			if err == nil || !strings.Contains(err.Error(), "requires --out") {
+				t.Errorf("export %s without --out: got %v", format, err)
+			}
+		}
+	})
+}
+
+func TestWriteMan_NameCollision(t *testing.T) {
+	t.Parallel()
+
+	idx := &Index{Sections: []Section{
+		{Slug: "using-k6", Category: "using-k6", Children: []string{"using-k6/a-b", "using-k6/a"}},
+		{Slug: "using-k6/a-b", Category: "using-k6", Weight: 1},
+		{Slug: "using-k6/a", Category: "using-k6", Weight: 2, Children: []string{"using-k6/a/b"}},
+		{Slug: "using-k6/a/b", Category: "using-k6"},
+	}}
+	idx.reindex()
+
+	err := writeMan(fsext.NewMemMapFs(), &bytes.Buffer{}, idx, "/tmp/man", "/cache", "v1.0.0")
+	want := "man page k6-docs-using-k6-a-b of using-k6/a/b would overwrite the page of using-k6/a-b"
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+}
+
+func TestWriteHTML_Links(t *testing.T) {
+	t.Parallel()
+
+	afs := fsext.NewMemMapFs()
+	page := "## Checks\n\n" +
+		"See [thresholds](https://grafana.com/docs/k6/<K6_VERSION>/using-k6/thresholds/#syntax), " +
+		"[the blog](https://grafana.com/blog/), [extensions](https://grafana.com/docs/k6/<K6_VERSION>/extensions/) " +
+		"and [a relative link](../other/).\n\n" +
+		"| Option | Docs |\n| --- | --- |\n| `abortOnFail` | [thresholds](https://grafana.com/docs/k6/<K6_VERSION>/using-k6/thresholds/) |\n"
+	if err := fsext.WriteFile(afs, "/cache/markdown/using-k6/checks.md", []byte(page), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx := &Index{Sections: []Section{
+		{Slug: "using-k6", Title: "Using k6", Category: "using-k6", Children: []string{"using-k6/checks", "using-k6/thresholds"}},
+		{Slug: "using-k6/checks", RelPath: "using-k6/checks.md", Title: "Checks", Category: "using-k6"},
+		{Slug: "using-k6/thresholds", Title: "Thresholds", Category: "using-k6"},
+	}}
+	idx.reindex()
+
+	if err := writeHTML(afs, &bytes.Buffer{}, idx, "/site", "/cache", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fsext.ReadFile(afs, "/site/using-k6/checks/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		`See <a href="../../using-k6/thresholds/index.html#syntax">thresholds</a>, `,
+		`<a href="https://grafana.com/blog/">the blog</a>, `,
+		`<a href="https://grafana.com/docs/k6/v1.0.0/extensions/">extensions</a>`,
+		"and a relative link.</p>",
+		`<td><a href="../../using-k6/thresholds/index.html">thresholds</a></td>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "[^") {
+		t.Errorf("links should not become footnotes:\n%s", out)
+	}
+}
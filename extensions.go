@@ -0,0 +1,299 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/ext"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// k6Module is the module path of k6 itself.
+const k6Module = "go.k6.io/k6"
+
+// extensionsConfig configures where docs for xk6 extensions are found.
+type extensionsConfig struct {
+	// Dir is a local directory of vendored extension docs, laid out as
+	// <dir>/<module path>/README.md or <dir>/<module name>.md.
+	Dir string `yaml:"dir"`
+	// Source is a URL template for extension docs. {path}, {name} and
+	// {version} are replaced with the module path, the last element of the
+	// module path, and the module version. Downloads are cached.
+	Source string `yaml:"source"`
+}
+
+// extensionInfo is a Go module linked into k6 that registers extensions.
+type extensionInfo struct {
+	Path    string
+	Version string
+	// Replaced is set when the module is replaced in the build, so its
+	// code may not match Version.
+	Replaced bool
+	// Names are the registered extensions, e.g. "k6/x/sql (js)".
+	Names []string
+}
+
+// reSemver matches a released module version, including pseudo-versions,
+// as opposed to "(devel)" for a module built from a local checkout.
+var reSemver = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// cacheable reports whether the docs of the extension can be cached by
+// version: only released versions of modules that are not replaced name the
+// same code in every build.
+func (e extensionInfo) cacheable() bool {
+	return !e.Replaced && reSemver.MatchString(e.Version)
+}
+
+// extensionsCacheDir returns the directory downloaded extension docs are
+// cached in, one file per module version. They do not depend on the docs
+// version, so they are kept apart from the docs cache. The layout is
+// ~/.local/share/k6/docs-extensions/.
+func extensionsCacheDir(env map[string]string) (string, error) {
+	dir, err := dataDir(env)
+	if err != nil {
+		return "", fmt.Errorf("extensions cache dir: %w", err)
+	}
+	return filepath.Join(dir, "docs-extensions"), nil
+}
+
+// Name returns the short name of the extension module, e.g. xk6-sql.
+func (e extensionInfo) Name() string {
+	return path.Base(e.Path)
+}
+
+// registeredExtensions returns every extension registered with k6.
+func registeredExtensions() []*ext.Extension {
+	all := ext.GetAll()
+	for _, e := range ext.Get(ext.SecretSourceExtension) {
+		all = append(all, e)
+	}
+	return all
+}
+
+// findExtensions groups registered extensions by the module that provides
+// them, using the modules listed in the build info. Extensions provided by
+// k6 itself are left out.
+func findExtensions(info *debug.BuildInfo, registered []*ext.Extension) []extensionInfo {
+	var modules []*debug.Module
+	if info != nil {
+		modules = append(modules, &info.Main)
+		modules = append(modules, info.Deps...)
+	}
+
+	byPath := make(map[string]*extensionInfo)
+	for _, e := range registered {
+		mod := extensionInfo{Path: e.Path, Version: e.Version}
+		best := 0
+		for _, m := range modules {
+			if m.Path == "" || len(m.Path) <= best {
+				continue
+			}
+			if e.Path != m.Path && !strings.HasPrefix(e.Path, m.Path+"/") && !strings.HasPrefix(e.Path, m.Path+".") {
+				continue
+			}
+			best = len(m.Path)
+			mod = extensionInfo{Path: m.Path, Version: m.Version, Replaced: m.Replace != nil}
+			if m.Replace != nil && m.Replace.Version != "" {
+				mod.Version = m.Replace.Version
+			}
+		}
+		if mod.Path == k6Module {
+			continue
+		}
+
+		if byPath[mod.Path] == nil {
+			byPath[mod.Path] = &mod
+		}
+		name := e.Name
+		if e.Type == ext.JSExtension && !strings.HasPrefix(name, "k6/") {
+			name = "k6/x/" + name
+		}
+		byPath[mod.Path].Names = append(byPath[mod.Path].Names, fmt.Sprintf("%s (%s)", name, e.Type))
+	}
+
+	exts := make([]extensionInfo, 0, len(byPath))
+	for _, e := range byPath {
+		sort.Strings(e.Names)
+		exts = append(exts, *e)
+	}
+	sort.Slice(exts, func(i, j int) bool { return exts[i].Path < exts[j].Path })
+	return exts
+}
+
+// lookupExtension finds an extension by module path, short module name, or
+// registered name such as k6/x/sql or sql.
+func lookupExtension(exts []extensionInfo, name string) (extensionInfo, bool) {
+	name = strings.ToLower(name)
+	for _, e := range exts {
+		if strings.ToLower(e.Path) == name || strings.ToLower(e.Name()) == name {
+			return e, true
+		}
+		for _, n := range e.Names {
+			reg, _, _ := strings.Cut(n, " ")
+			reg = strings.ToLower(reg)
+			if reg == name || strings.TrimPrefix(reg, "k6/x/") == name {
+				return e, true
+			}
+		}
+	}
+	return extensionInfo{}, false
+}
+
+// errNoExtensionDocs is returned when no docs are available for an extension.
+var errNoExtensionDocs = errors.New("no docs available")
+
+// extensionDocs returns the docs of an extension from the vendored directory,
+// the download cache, or the configured source, in that order. Downloads are
+// only cached for released, unreplaced module versions.
+func extensionDocs(
+	afs fsext.Fs, env map[string]string, client HTTPClient, cfg extensionsConfig, e extensionInfo,
+) (string, error) {
+	if cfg.Dir != "" {
+		dir, err := overlayDir(env, cfg.Dir)
+		if err != nil {
+			return "", err
+		}
+		for _, p := range []string{
+			filepath.Join(dir, filepath.FromSlash(e.Path), "README.md"),
+			filepath.Join(dir, e.Name()+".md"),
+		} {
+			if data, err := fsext.ReadFile(afs, p); err == nil {
+				return string(data), nil
+			}
+		}
+	}
+
+	if cfg.Source == "" {
+		return "", errNoExtensionDocs
+	}
+
+	cached := ""
+	if e.cacheable() {
+		cacheDir, err := extensionsCacheDir(env)
+		if err != nil {
+			return "", err
+		}
+		cached = filepath.Join(cacheDir, filepath.FromSlash(e.Path), e.Version+".md")
+		if data, err := fsext.ReadFile(afs, cached); err == nil {
+			return string(data), nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	url := strings.NewReplacer("{path}", e.Path, "{name}", e.Name(), "{version}", e.Version).Replace(cfg.Source)
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", errNoExtensionDocs
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("download %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", url, err)
+	}
+	if cached != "" {
+		if err := afs.MkdirAll(filepath.Dir(cached), 0o750); err == nil {
+			_ = fsext.WriteFile(afs, cached, data, 0o600)
+		}
+	}
+	return string(data), nil
+}
+
+func newExtensionsCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	return &cobra.Command{
+		Use:   "extensions [name]",
+		Short: "List the xk6 extensions in this k6 build and show their docs",
+		Long: "Without a name, list the xk6 extensions linked into this k6 binary.\n" +
+			"With a name, print the extension's README from the extensions.dir or\n" +
+			"extensions.source configured in docs.yaml.",
+		Example: "  k6 x docs extensions\n  k6 x docs extensions xk6-sql",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info, _ := debug.ReadBuildInfo()
+			return runExtensions(gs, cmd, opts, args, findExtensions(info, registeredExtensions()), http.DefaultClient)
+		},
+	}
+}
+
+func runExtensions(
+	gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string, exts []extensionInfo, client HTTPClient,
+) error {
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+
+	cfg, cfgErr := loadConfig(gs.FS, gs.Env)
+	if cfgErr != nil {
+		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
+	}
+
+	if len(args) == 0 {
+		w, render := newOutput(gs, cmd, cfg, opts)
+		printExtensions(w, exts)
+		return render()
+	}
+
+	e, ok := lookupExtension(exts, args[0])
+	if !ok {
+		return fmt.Errorf("extension not found: %s\n\nList extensions: k6 x docs extensions", args[0])
+	}
+
+	content, err := extensionDocs(gs.FS, gs.Env, client, cfg.Extensions, e)
+	if errors.Is(err, errNoExtensionDocs) {
+		return fmt.Errorf("no docs found for %s %s\n\n"+
+			"Vendor its README as <dir>/%s/README.md and set extensions.dir in docs.yaml,\n"+
+			"or set extensions.source to a URL template such as https://example.com/{path}/{version}/README.md",
+			e.Path, e.Version, e.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("extension docs: %w", err)
+	}
+
+	w, render := newOutput(gs, cmd, cfg, opts)
+	_, _ = fmt.Fprint(w, StripFrontmatter(content))
+	if !strings.HasSuffix(content, "\n") {
+		_, _ = fmt.Fprintln(w)
+	}
+	return render()
+}
+
+// printExtensions lists extension modules with their version and the
+// extensions they register.
+func printExtensions(w io.Writer, exts []extensionInfo) {
+	if len(exts) == 0 {
+		_, _ = fmt.Fprintln(w, "No xk6 extensions are linked into this k6 build.")
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "xk6 extensions in this k6 build")
+	_, _ = fmt.Fprintln(w, "Use: k6 x docs extensions <name>")
+	_, _ = fmt.Fprintln(w)
+
+	items := make([]listItem, 0, len(exts))
+	for _, e := range exts {
+		items = append(items, listItem{
+			Name:        e.Name(),
+			Description: strings.TrimSpace(e.Version + "  " + strings.Join(e.Names, ", ")),
+		})
+	}
+	printAlignedList(w, items)
+}
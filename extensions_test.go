@@ -0,0 +1,225 @@
+package docs
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/ext"
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestFindExtensions(t *testing.T) {
+	t.Parallel()
+
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "k6"},
+		Deps: []*debug.Module{
+			{Path: "go.k6.io/k6", Version: "v1.5.0"},
+			{Path: "github.com/grafana/xk6-sql", Version: "v1.0.0"},
+			{Path: "github.com/grafana/xk6-sql-driver-sqlite3", Version: "v0.1.0"},
+			{
+				Path: "github.com/grafana/xk6-dashboard", Version: "v0.7.0",
+				Replace: &debug.Module{Path: "../xk6-dashboard", Version: "v0.7.1"},
+			},
+		},
+	}
+	registered := []*ext.Extension{
+		{Name: "k6/x/sql", Path: "github.com/grafana/xk6-sql/sql", Type: ext.JSExtension},
+		{Name: "k6/x/sql/driver/sqlite3", Path: "github.com/grafana/xk6-sql-driver-sqlite3", Type: ext.JSExtension},
+		{Name: "dashboard", Path: "github.com/grafana/xk6-dashboard/dashboard", Type: ext.OutputExtension},
+		{Name: "json", Path: "go.k6.io/k6/output/json", Type: ext.OutputExtension},
+	}
+
+	got := findExtensions(info, registered)
+
+	want := []extensionInfo{
+		{Path: "github.com/grafana/xk6-dashboard", Version: "v0.7.1", Names: []string{"dashboard (output)"}},
+		{Path: "github.com/grafana/xk6-sql", Version: "v1.0.0", Names: []string{"k6/x/sql (js)"}},
+		{
+			Path: "github.com/grafana/xk6-sql-driver-sqlite3", Version: "v0.1.0",
+			Names: []string{"k6/x/sql/driver/sqlite3 (js)"},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findExtensions() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Path != want[i].Path || got[i].Version != want[i].Version ||
+			strings.Join(got[i].Names, ",") != strings.Join(want[i].Names, ",") {
+			t.Errorf("findExtensions()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	for _, name := range []string{"xk6-sql", "k6/x/sql", "sql", "github.com/grafana/xk6-sql"} {
+		if e, ok := lookupExtension(got, name); !ok || e.Path != "github.com/grafana/xk6-sql" {
+			t.Errorf("lookupExtension(%q) = %+v, %v", name, e, ok)
+		}
+	}
+	if _, ok := lookupExtension(got, "xk6-missing"); ok {
+		t.Error("lookupExtension(xk6-missing) found an extension")
+	}
+}
+
+func TestExtensionDocs(t *testing.T) {
+	t.Parallel()
+
+	sql := extensionInfo{Path: "github.com/grafana/xk6-sql", Version: "v1.0.0"}
+	env := map[string]string{"HOME": "/home/test", "XDG_CONFIG_HOME": "/home/test/.config"}
+
+	t.Run("vendored", func(t *testing.T) {
+		t.Parallel()
+		afs := fsext.NewMemMapFs()
+		path := "/home/test/.config/k6/extensions/xk6-sql.md"
+		if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsext.WriteFile(afs, path, []byte("# xk6-sql\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := extensionDocs(afs, env, nil, extensionsConfig{Dir: "extensions"}, sql)
+		if err != nil || got != "# xk6-sql\n" {
+			t.Errorf("extensionDocs() = %q, %v", got, err)
+		}
+	})
+
+	t.Run("source_is_cached", func(t *testing.T) {
+		t.Parallel()
+		afs := fsext.NewMemMapFs()
+		mock := &mockHTTPClient{body: []byte("# From source\n"), statusCode: http.StatusOK}
+		cfg := extensionsConfig{Source: "https://example.com/{path}/{version}/README.md"}
+
+		for range 2 {
+			got, err := extensionDocs(afs, env, mock, cfg, sql)
+			if err != nil || got != "# From source\n" {
+				t.Errorf("extensionDocs() = %q, %v", got, err)
+			}
+		}
+		if mock.calls != 1 {
+			t.Errorf("expected 1 HTTP call, got %d", mock.calls)
+		}
+		cached := "/home/test/.local/share/k6/docs-extensions/github.com/grafana/xk6-sql/v1.0.0.md"
+		if _, err := afs.Stat(cached); err != nil {
+			t.Errorf("expected the download cached at %s: %v", cached, err)
+		}
+	})
+
+	t.Run("devel_not_cached", func(t *testing.T) {
+		t.Parallel()
+		cfg := extensionsConfig{Source: "https://example.com/{path}/{version}/README.md"}
+		for _, e := range []extensionInfo{
+			{Path: "github.com/grafana/xk6-sql", Version: "(devel)"},
+			{Path: "github.com/grafana/xk6-sql", Version: "v1.0.0", Replaced: true},
+		} {
+			afs := fsext.NewMemMapFs()
+			mock := &mockHTTPClient{body: []byte("# From source\n"), statusCode: http.StatusOK}
+			for range 2 {
+				if _, err := extensionDocs(afs, env, mock, cfg, e); err != nil {
+					t.Fatalf("extensionDocs(%+v): %v", e, err)
+				}
+			}
+			if mock.calls != 2 {
+				t.Errorf("%+v: expected 2 HTTP calls, got %d", e, mock.calls)
+			}
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		t.Parallel()
+		afs := fsext.NewMemMapFs()
+		mock := &mockHTTPClient{statusCode: http.StatusNotFound}
+		cfg := extensionsConfig{Source: "https://example.com/{name}.md"}
+
+		if _, err := extensionDocs(afs, env, mock, cfg, sql); !errors.Is(err, errNoExtensionDocs) {
+			t.Errorf("extensionDocs() error = %v, want errNoExtensionDocs", err)
+		}
+	})
+
+	t.Run("unconfigured", func(t *testing.T) {
+		t.Parallel()
+		_, err := extensionDocs(fsext.NewMemMapFs(), env, nil, extensionsConfig{}, sql)
+		if !errors.Is(err, errNoExtensionDocs) {
+			t.Errorf("extensionDocs() error = %v, want errNoExtensionDocs", err)
+		}
+	})
+}
+
+func TestExtensionsCommand(t *testing.T) {
+	t.Parallel()
+
+	exts := []extensionInfo{
+		{Path: "github.com/grafana/xk6-dashboard", Version: "v0.7.1", Names: []string{"dashboard (output)"}},
+		{Path: "github.com/grafana/xk6-sql", Version: "v1.0.0", Names: []string{"k6/x/sql (js)"}},
+	}
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		afs := fsext.NewMemMapFs()
+		gs := newTestGlobalState(t, afs)
+		gs.Env["HOME"] = "/home/test"
+		gs.Env["XDG_CONFIG_HOME"] = "/home/test/.config"
+
+		files := map[string]string{
+			"/home/test/.config/k6/docs.yaml": "extensions:\n  dir: ~/k6-extensions\n",
+			"/home/test/k6-extensions/github.com/grafana/xk6-sql/README.md": "---\ntitle: xk6-sql\n---\n\n" +
+				"# xk6-sql\n\nUse SQL databases from k6 scripts.\n",
+		}
+		for path, content := range files {
+			if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsext.WriteFile(afs, path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var buf bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&buf)
+		opts := &docsOpts{format: formatMarkdown}
+		err := runExtensions(gs, cmd, opts, args, exts, &mockHTTPClient{statusCode: http.StatusNotFound})
+		return buf.String(), err
+	}
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "extensions/list.txt", out)
+	})
+
+	t.Run("docs", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "# xk6-sql\n\nUse SQL databases from k6 scripts.\n"; strings.TrimLeft(out, "\n") != want {
+			t.Errorf("docs = %q, want %q", out, want)
+		}
+	})
+
+	t.Run("no_docs", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, "xk6-dashboard")
+		if err == nil || !strings.Contains(err.Error(), "no docs found for github.com/grafana/xk6-dashboard") {
+			t.Errorf("expected no docs error, got %v", err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, "xk6-missing")
+		if err == nil || !strings.Contains(err.Error(), "extension not found: xk6-missing") {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package docs
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// reFootnote matches a footnote definition written by the links stage.
+var reFootnote = regexp.MustCompile(`^\[\^\d+\]: \S+$`)
+
+// reFootnoteRef matches a footnote reference, as in "text[^1]".
+var reFootnoteRef = regexp.MustCompile(`\[\^(\d+)\]`)
+
+// externalURL returns the URL of a link destination, without its title,
+// and whether it points to another site.
+func externalURL(dest string) (string, bool) {
+	fields := strings.Fields(dest)
+	if len(fields) == 0 {
+		return "", false
+	}
+	u := strings.Trim(fields[0], "<>")
+	return u, strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://")
+}
+
+// rewriteLinks replaces each markdown link in s with repl(text, dest).
+// Images are left alone, so that they are kept when the images stage is
+// disabled.
+func rewriteLinks(s string, repl func(text, dest string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range reMarkdownLink.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > 0 && s[m[0]-1] == '!' {
+			continue
+		}
+		sb.WriteString(s[last:m[0]])
+		sb.WriteString(repl(s[m[2]:m[3]], s[m[4]:m[5]]))
+		last = m[1]
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+// link returns a link with text and dest as written out. Links to included
+// docs pages become their text with internal-links. Links to other sites
+// become "text (url)" with inline-links, and their text with links, which
+// keeps external URLs as numbered footnote references, text[^1], defined at
+// the end of the content as "[^1]: url". Unlike "[1]: url", which markdown
+// renderers take for a link reference definition and hide, footnotes stay
+// visible. A URL linked several times gets a single footnote, and a link
+// whose text is its URL needs none.
+func (t *transformer) link(text, dest string) string {
+	if t.on(stageInternalLinks) {
+		if path, ok := internalDocsPath(dest); ok && IsIncludedDocsPath(path) {
+			return text
+		}
+	}
+	u, web := externalURL(dest)
+	switch {
+	case web && t.on(stageInlineLinks):
+		if u == text {
+			return u
+		}
+		return text + " (" + u + ")"
+	case t.on(stageLinks):
+		if !web || u == text {
+			return text
+		}
+		n := slices.Index(t.urls, u)
+		if n < 0 {
+			t.urls = append(t.urls, u)
+			n = len(t.urls) - 1
+		}
+		return text + footnoteRef(n+1)
+	}
+	return "[" + text + "](" + dest + ")"
+}
+
+// footnoteRef returns the reference to footnote n, e.g. "[^1]".
+func footnoteRef(n int) string {
+	return "[^" + strconv.Itoa(n) + "]"
+}
+
+// cutFootnotes splits the footnote definitions written by the links stage
+// off the end of content.
+func cutFootnotes(content string) (string, string) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	i := len(lines)
+	for i > 0 && reFootnote.MatchString(lines[i-1]) {
+		i--
+	}
+	if i == len(lines) {
+		return content, ""
+	}
+	body := strings.TrimRight(strings.Join(lines[:i], "\n"), "\n") + "\n"
+	return body, strings.Join(lines[i:], "\n") + "\n"
+}
+
+// appendFootnotes appends the footnote definitions that part refers to, so
+// that an excerpt of a section keeps the URLs of its links. References are
+// looked up in prose only, since code may contain the same text, e.g. a
+// regular expression with [^1].
+func appendFootnotes(part, footnotes string) string {
+	prose, _ := maskCode(part)
+	var kept []string
+	for _, def := range strings.Split(strings.TrimRight(footnotes, "\n"), "\n") {
+		ref, _, ok := strings.Cut(def, ":")
+		if ok && strings.Contains(prose, ref) {
+			kept = append(kept, def)
+		}
+	}
+	if len(kept) == 0 {
+		return part
+	}
+	return strings.TrimRight(part, "\n") + "\n\n" + strings.Join(kept, "\n") + "\n"
+}
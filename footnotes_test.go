@@ -0,0 +1,47 @@
+package docs
+
+import "testing"
+
+func TestFootnotesFollowExcerpts(t *testing.T) {
+	t.Parallel()
+
+	content := Transform("## Install\n\nGet [xk6](https://github.com/grafana/xk6).\n\n"+
+		"## Import\n\nUse [jslib](https://jslib.k6.io/) or [xk6](https://github.com/grafana/xk6).\n\n"+
+		"## Other\n\nNo links.\n", "v1.0.0")
+
+	body, footnotes := cutFootnotes(content)
+	if want := "[^1]: https://github.com/grafana/xk6\n[^2]: https://jslib.k6.io/\n"; footnotes != want {
+		t.Fatalf("footnotes = %q, want %q", footnotes, want)
+	}
+
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{"install", "## Install\n\nGet xk6[^1].\n\n[^1]: https://github.com/grafana/xk6\n"},
+		{
+			"import",
+			"## Import\n\nUse jslib[^2] or xk6[^1].\n\n[^1]: https://github.com/grafana/xk6\n[^2]: https://jslib.k6.io/\n",
+		},
+		{"other", "## Other\n\nNo links.\n"},
+	}
+	for _, tt := range tests {
+		sub, ok := ExtractHeading(body, tt.heading)
+		if !ok {
+			t.Fatalf("heading %q not found", tt.heading)
+		}
+		if got := appendFootnotes(sub, footnotes); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.heading, got, tt.want)
+		}
+	}
+
+	// References in code are not footnote references.
+	code := "## Match\n\n```js\nif (/[^1]/.test(s)) {}\n```\n\nSee `arr[^2]`.\n"
+	if got := appendFootnotes(code, footnotes); got != code {
+		t.Errorf("code: got %q, want no footnotes", got)
+	}
+
+	if got, notes := cutFootnotes("No links.\n"); got != "No links.\n" || notes != "" {
+		t.Errorf("cutFootnotes without footnotes = %q, %q", got, notes)
+	}
+}
@@ -0,0 +1,233 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// Heading is a markdown ATX heading found in a section's content.
+type Heading struct {
+	Level  int
+	Text   string
+	Anchor string
+	// Line is the zero-based line index of the heading in the content.
+	Line int
+}
+
+// fence is the opening line of a fenced code block: its character, ` or ~,
+// and its length. The zero value is outside any block.
+type fence struct {
+	char byte
+	n    int
+}
+
+// openFence returns the fence that line opens, if it is a fence line: three
+// or more backticks or tildes, and for backticks an info string without any.
+func openFence(line string) (fence, bool) {
+	trimmed := strings.TrimSpace(line)
+	if len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return fence{}, false
+	}
+	f := fence{char: trimmed[0]}
+	for f.n < len(trimmed) && trimmed[f.n] == f.char {
+		f.n++
+	}
+	if f.n < 3 || (f.char == '`' && strings.Contains(trimmed[f.n:], "`")) {
+		return fence{}, false
+	}
+	return f, true
+}
+
+// closes reports whether line closes the block f opened: a run of the same
+// character, at least as long, and nothing else.
+func (f fence) closes(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= f.n && strings.Trim(trimmed, string(f.char)) == ""
+}
+
+// inside reports whether f is an open block.
+func (f fence) inside() bool {
+	return f.n > 0
+}
+
+// step moves f past line and reports whether line is a fence line, one that
+// opens or closes a block. Inside a block, only a matching fence closes it,
+// so that a ````markdown block can show a ```js block.
+func (f *fence) step(line string) bool {
+	if f.inside() {
+		if f.closes(line) {
+			*f = fence{}
+			return true
+		}
+		return false
+	}
+	open, ok := openFence(line)
+	if ok {
+		*f = open
+	}
+	return ok
+}
+
+// codeBody returns the lines of a fenced code block, without the opening
+// fence and, if it has one, the closing fence.
+func codeBody(text string) []string {
+	lines := strings.Split(text, "\n")
+	f, _ := openFence(lines[0])
+	lines = lines[1:]
+	if len(lines) > 0 && f.closes(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// parseHeading parses an ATX heading line ("## Title"). It returns the level
+// and the heading text, or 0 if the line is not a heading.
+func parseHeading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0, ""
+	}
+	text := strings.TrimSpace(strings.TrimRight(line[level:], "# "))
+	return level, text
+}
+
+// headingAnchor derives a GitHub/Hugo style anchor from heading text:
+// lowercase, spaces become dashes, and punctuation other than dashes and
+// underscores is dropped.
+func headingAnchor(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ParseHeadings returns the headings in content in document order.
+// Lines inside fenced code blocks are ignored.
+func ParseHeadings(content string) []Heading {
+	var headings []Heading
+	var f fence
+
+	for i, line := range strings.Split(content, "\n") {
+		if f.step(line) || f.inside() {
+			continue
+		}
+		level, text := parseHeading(line)
+		if level == 0 {
+			continue
+		}
+		headings = append(headings, Heading{
+			Level:  level,
+			Text:   text,
+			Anchor: headingAnchor(text),
+			Line:   i,
+		})
+	}
+
+	return headings
+}
+
+// findHeading returns the heading matching name by anchor or by normalized
+// text, so "Executors", "executors" and "#executors" are all accepted.
+func findHeading(headings []Heading, name string) (Heading, bool) {
+	name = strings.TrimPrefix(name, "#")
+	anchor := headingAnchor(name)
+	norm := normalize(name)
+
+	for _, h := range headings {
+		if h.Anchor == anchor || normalize(h.Text) == norm {
+			return h, true
+		}
+	}
+	return Heading{}, false
+}
+
+// ExtractHeading returns the part of content under the heading matching name,
+// from the heading line up to the next heading of the same or higher level.
+func ExtractHeading(content, name string) (string, bool) {
+	headings := ParseHeadings(content)
+	h, ok := findHeading(headings, name)
+	if !ok {
+		return "", false
+	}
+
+	lines := strings.Split(content, "\n")
+	end := len(lines)
+	for _, next := range headings {
+		if next.Line > h.Line && next.Level <= h.Level {
+			end = next.Line
+			break
+		}
+	}
+
+	return strings.TrimRight(strings.Join(lines[h.Line:end], "\n"), "\n") + "\n", true
+}
+
+// splitAnchor splits a trailing "#anchor" off the last arg, so that
+// "scenarios#executors" selects the executors heading of scenarios.
+func splitAnchor(args []string) ([]string, string) {
+	if len(args) == 0 {
+		return args, ""
+	}
+	last := args[len(args)-1]
+	i := strings.Index(last, "#")
+	if i < 0 {
+		return args, ""
+	}
+
+	out := append([]string{}, args[:len(args)-1]...)
+	if i > 0 {
+		out = append(out, last[:i])
+	}
+	return out, last[i+1:]
+}
+
+// printOutline prints the heading tree of a section with anchors.
+func printOutline(afs fsext.Fs, w io.Writer, idx *Index, section *Section, cacheDir, version string) {
+	content := readAndTransform(afs, idx, cacheDir, section, version)
+	headings := ParseHeadings(content)
+
+	_, _ = fmt.Fprintln(w, section.Title)
+
+	if len(headings) == 0 {
+		_, _ = fmt.Fprintln(w, "\n  (no headings)")
+		return
+	}
+
+	minLevel := headings[0].Level
+	for _, h := range headings {
+		minLevel = min(minLevel, h.Level)
+	}
+
+	for _, h := range headings {
+		indent := strings.Repeat("  ", h.Level-minLevel)
+		_, _ = fmt.Fprintf(w, "%s- %s (#%s)\n", indent, h.Text, h.Anchor)
+	}
+
+	_, _ = fmt.Fprintf(w, "\nUse: k6 x docs %s --heading <heading>\n", commandArgs(idx, section.Slug))
+}
+
+// printHeading prints only the subsection of a section under the given heading.
+func printHeading(
+	afs fsext.Fs, w io.Writer, idx *Index, section *Section, heading, cacheDir, version string,
+) error {
+	content, footnotes := cutFootnotes(readAndTransform(afs, idx, cacheDir, section, version))
+	sub, ok := ExtractHeading(content, heading)
+	if !ok {
+		return fmt.Errorf("heading not found in %s: %s (use --outline to list headings)",
+			commandArgs(idx, section.Slug), heading)
+	}
+	_, _ = fmt.Fprint(w, appendFootnotes(sub, footnotes))
+	return nil
+}
@@ -0,0 +1,156 @@
+package docs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestParseHeadings(t *testing.T) {
+	t.Parallel()
+
+	content := "# Title\n\nIntro.\n\n## First Part\n\n```js\n# not a heading\n```\n\n### Deep: detail!\n\n## Second\n#nospace\n"
+	got := ParseHeadings(content)
+
+	want := []Heading{
+		{Level: 1, Text: "Title", Anchor: "title", Line: 0},
+		{Level: 2, Text: "First Part", Anchor: "first-part", Line: 4},
+		{Level: 3, Text: "Deep: detail!", Anchor: "deep-detail", Line: 10},
+		{Level: 2, Text: "Second", Anchor: "second", Line: 12},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseHeadings() returned %d headings, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("heading[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A shorter fence inside a block does not close it.
+	got = ParseHeadings("````markdown\n```\n# Not a heading\n```\n# Nor this\n````\n## Heading\n")
+	if len(got) != 1 || got[0].Text != "Heading" {
+		t.Errorf("ParseHeadings() with a nested fence = %+v, want only Heading", got)
+	}
+}
+
+func TestExtractHeading(t *testing.T) {
+	t.Parallel()
+
+	content := "# Title\n\nIntro.\n\n## A\n\nA body.\n\n### A.1\n\nNested.\n\n## B\n\nB body.\n"
+
+	tests := []struct {
+		name    string
+		heading string
+		want    string
+		found   bool
+	}{
+		{"includes nested headings", "A", "## A\n\nA body.\n\n### A.1\n\nNested.\n", true},
+		{"last heading runs to end", "b", "## B\n\nB body.\n", true},
+		{"by anchor", "#a1", "### A.1\n\nNested.\n", true},
+		{"missing", "C", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ExtractHeading(content, tt.heading)
+			if ok != tt.found {
+				t.Fatalf("ExtractHeading(%q) found = %v, want %v", tt.heading, ok, tt.found)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractHeading(%q) = %q, want %q", tt.heading, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitAnchor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantArgs   []string
+		wantAnchor string
+	}{
+		{"no anchor", []string{"using-k6", "scenarios"}, []string{"using-k6", "scenarios"}, ""},
+		{"attached anchor", []string{"using-k6", "scenarios#executors"}, []string{"using-k6", "scenarios"}, "executors"},
+		{"detached anchor", []string{"using-k6", "scenarios", "#executors"}, []string{"using-k6", "scenarios"}, "executors"},
+		{"full slug", []string{"using-k6/scenarios#executors"}, []string{"using-k6/scenarios"}, "executors"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gotArgs, gotAnchor := splitAnchor(tt.args)
+			if gotAnchor != tt.wantAnchor {
+				t.Errorf("anchor = %q, want %q", gotAnchor, tt.wantAnchor)
+			}
+			if len(gotArgs) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			for i := range gotArgs {
+				if gotArgs[i] != tt.wantArgs[i] {
+					t.Errorf("args = %v, want %v", gotArgs, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestHeadingCommand(t *testing.T) {
+	t.Parallel()
+
+	run, runErr := setupCommand(t)
+
+	t.Run("outline", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "view/using-k6-scenarios-outline.txt", run(t, "using-k6", "scenarios", "--outline"))
+	})
+	t.Run("heading_flag", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "view/using-k6-scenarios-executors.txt", run(t, "using-k6", "scenarios", "--heading", "Executors"))
+	})
+	t.Run("anchor_suffix", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "view/using-k6-scenarios-executors.txt", run(t, "using-k6", "scenarios#executors"))
+	})
+	t.Run("unknown_heading", func(t *testing.T) {
+		t.Parallel()
+		err := runErr(t, "using-k6", "scenarios", "--heading", "nope")
+		if err == nil {
+			t.Fatal("expected error for unknown heading")
+		}
+		assertGolden(t, "errors/unknown-heading.txt", err.Error())
+	})
+}
+
+func TestPrintOutline_CommandArgs(t *testing.T) {
+	t.Parallel()
+
+	afs := fsext.NewMemMapFs()
+	idx := &Index{}
+	for _, slug := range []string{"javascript-api/jslib", "javascript-api/k6-jslib"} {
+		rel := slug + ".md"
+		if err := fsext.WriteFile(afs, filepath.Join("/cache", "markdown", rel), []byte("## Usage\n\nText.\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		idx.Sections = append(idx.Sections, Section{Slug: slug, RelPath: rel, Category: "javascript-api"})
+	}
+	idx.reindex()
+
+	var buf bytes.Buffer
+	printOutline(afs, &buf, idx, &idx.Sections[1], "/cache", "v1.0.0")
+	// "jslib" opens javascript-api/jslib, so the full slug is printed.
+	if want := "Use: k6 x docs javascript-api/k6-jslib --heading <heading>"; !strings.Contains(buf.String(), want) {
+		t.Errorf("missing %q:\n%s", want, buf.String())
+	}
+	err := printHeading(afs, &buf, idx, &idx.Sections[1], "nope", "/cache", "v1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "heading not found in javascript-api/k6-jslib:") {
+		t.Errorf("err = %v, want the full slug", err)
+	}
+}
@@ -0,0 +1,267 @@
+package docs
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// historySize is the number of recently opened topics that are remembered.
+const historySize = 30
+
+// lastTopic is the argument that reopens the most recently opened topic.
+const lastTopic = "-"
+
+// Files in the data directory that hold one slug per line.
+const (
+	historyFile   = "docs-history"
+	bookmarksFile = "docs-bookmarks"
+)
+
+// slugListPath returns the path of a slug list file in the data directory.
+func slugListPath(env map[string]string, name string) (string, error) {
+	dir, err := dataDir(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// readSlugList returns the slugs stored in a slug list file, or nil if it
+// does not exist.
+func readSlugList(afs fsext.Fs, env map[string]string, name string) ([]string, error) {
+	path, err := slugListPath(env, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fsext.ReadFile(afs, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// writeSlugList replaces the slugs stored in a slug list file. The list is
+// written to a temporary file that is renamed over the old one, so that a
+// concurrent reader never sees a partly written list.
+func writeSlugList(afs fsext.Fs, env map[string]string, name string, slugs []string) error {
+	path, err := slugListPath(env, name)
+	if err != nil {
+		return err
+	}
+	if err := afs.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+	content := ""
+	if len(slugs) > 0 {
+		content = strings.Join(slugs, "\n") + "\n"
+	}
+	tmp := path + "." + rand.Text() + ".tmp"
+	if err := fsext.WriteFile(afs, tmp, []byte(content), 0o600); err != nil {
+		_ = afs.Remove(tmp)
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if err := afs.Rename(tmp, path); err != nil {
+		_ = afs.Remove(tmp)
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// recordHistory moves slug to the front of the history, most recent first,
+// and drops the oldest entries beyond historySize.
+func recordHistory(afs fsext.Fs, env map[string]string, slug string) error {
+	history, err := readSlugList(afs, env, historyFile)
+	if err != nil {
+		return err
+	}
+	history = slices.DeleteFunc(history, func(s string) bool { return s == slug })
+	history = append([]string{slug}, history...)
+	if len(history) > historySize {
+		history = history[:historySize]
+	}
+	return writeSlugList(afs, env, historyFile, history)
+}
+
+// lastSlug returns the most recently opened topic.
+func lastSlug(afs fsext.Fs, env map[string]string) (string, error) {
+	history, err := readSlugList(afs, env, historyFile)
+	if err != nil {
+		return "", err
+	}
+	if len(history) == 0 {
+		return "", errors.New("no recent topics yet; open one with: k6 x docs <topic>")
+	}
+	return history[0], nil
+}
+
+func newRecentCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	return &cobra.Command{
+		Use:     "recent",
+		Short:   "List recently opened topics",
+		Long:    "List recently opened topics, most recent first. Reopen the last one with: k6 x docs -",
+		Example: "  k6 x docs recent\n  k6 x docs -",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSlugList(gs, cmd, opts, historyFile)
+		},
+	}
+}
+
+func newBookmarkCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bookmark",
+		Short: "Bookmark topics you return to often",
+		Example: "  k6 x docs bookmark add http params\n  k6 x docs bookmark list\n" +
+			"  k6 x docs bookmark rm http params",
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <topic> [subtopic...]",
+		Short: "Bookmark a topic",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBookmarkAdd(gs, cmd, opts, args)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List bookmarked topics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSlugList(gs, cmd, opts, bookmarksFile)
+		},
+	}
+
+	rmCmd := &cobra.Command{
+		Use:   "rm <topic> [subtopic...]",
+		Short: "Remove a bookmark",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBookmarkRm(gs, cmd, opts, args)
+		},
+	}
+
+	cmd.AddCommand(addCmd, listCmd, rmCmd)
+	return cmd
+}
+
+func runBookmarkAdd(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string) error {
+	_, _, idx, err := setup(gs, opts)
+	if err != nil {
+		return err
+	}
+	sec, err := resolveTopic(cmd.ErrOrStderr(), idx, args)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, err := readSlugList(gs.FS, gs.Env, bookmarksFile)
+	if err != nil {
+		return err
+	}
+	name := commandArgs(idx, sec.Slug)
+	if slices.Contains(bookmarks, sec.Slug) {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Already bookmarked: %s\n", name)
+		return nil
+	}
+	if err := writeSlugList(gs.FS, gs.Env, bookmarksFile, append(bookmarks, sec.Slug)); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Bookmarked %s\n", name)
+	return nil
+}
+
+// runBookmarkRm removes a bookmark. The topic is resolved against the
+// bookmarks rather than the docs, so bookmarks of removed pages can be
+// removed too. The docs are only loaded to print the removed topic; when they
+// cannot be loaded, the full slug is printed.
+func runBookmarkRm(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string) error {
+	bookmarks, err := readSlugList(gs.FS, gs.Env, bookmarksFile)
+	if err != nil {
+		return err
+	}
+	slug := ResolveWithLookup(args, func(s string) bool { return slices.Contains(bookmarks, s) })
+	if !slices.Contains(bookmarks, slug) {
+		return fmt.Errorf("not bookmarked: %s\n\nList bookmarks: k6 x docs bookmark list", strings.Join(args, " "))
+	}
+
+	bookmarks = slices.DeleteFunc(bookmarks, func(s string) bool { return s == slug })
+	if err := writeSlugList(gs.FS, gs.Env, bookmarksFile, bookmarks); err != nil {
+		return err
+	}
+	name := slug
+	if _, _, idx, err := setup(gs, opts); err == nil {
+		name = commandArgs(idx, slug)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed bookmark %s\n", name)
+	return nil
+}
+
+// runSlugList prints the topics of the history or the bookmarks.
+func runSlugList(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, name string) error {
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+
+	slugs, err := readSlugList(gs.FS, gs.Env, name)
+	if err != nil {
+		return err
+	}
+
+	cfg, cfgErr := loadConfig(gs.FS, gs.Env)
+	if cfgErr != nil {
+		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
+	}
+
+	var idx *Index
+	if len(slugs) > 0 {
+		if _, _, idx, err = setup(gs, opts); err != nil {
+			return err
+		}
+	}
+
+	w, render := newOutput(gs, cmd, cfg, opts)
+	if name == historyFile {
+		printSlugList(w, idx, slugs, "Recent topics", "No recent topics yet.")
+	} else {
+		printSlugList(w, idx, slugs, "Bookmarks", "No bookmarks yet. Add one with: k6 x docs bookmark add <topic>")
+	}
+	return render()
+}
+
+// printSlugList lists topics with their description, like printTopLevelList.
+// Topics that no longer exist in the docs are listed without a description.
+func printSlugList(w io.Writer, idx *Index, slugs []string, title, empty string) {
+	if len(slugs) == 0 {
+		_, _ = fmt.Fprintln(w, empty)
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, title)
+	_, _ = fmt.Fprintln(w, "Use: k6 x docs <topic>")
+	_, _ = fmt.Fprintln(w)
+
+	items := make([]listItem, 0, len(slugs))
+	for _, slug := range slugs {
+		item := listItem{Name: commandArgs(idx, slug), Description: "(not in these docs)"}
+		if sec, ok := idx.Lookup(slug); ok {
+			item = listItem{Name: commandArgs(idx, slug), Description: truncate(sec.Description, 80)}
+		}
+		items = append(items, item)
+	}
+	printAlignedList(w, items)
+}
@@ -0,0 +1,162 @@
+package docs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRecordHistory(t *testing.T) {
+	t.Parallel()
+
+	afs, _ := setupTestCache(t)
+	env := map[string]string{"HOME": "/home/test"}
+
+	for i := range historySize + 5 {
+		if err := recordHistory(afs, env, "page-"+strings.Repeat("x", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recordHistory(afs, env, "page-x"); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := readSlugList(afs, env, historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != historySize {
+		t.Errorf("history has %d entries, want %d", len(history), historySize)
+	}
+	if history[0] != "page-x" || history[1] != "page-"+strings.Repeat("x", historySize+4) {
+		t.Errorf("history starts with %v", history[:2])
+	}
+	if strings.Count(strings.Join(history, " "), "page-x ") != 1 {
+		t.Errorf("page-x recorded twice: %v", history)
+	}
+}
+
+func TestHistoryAndBookmarks(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = "/home/test"
+	// History is only recorded at a terminal; plain output keeps it readable.
+	gs.Stdout.IsTTY = true
+	gs.Env["NO_COLOR"] = "1"
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "--no-pager"}, args...))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+	mustRun := func(t *testing.T, args ...string) string {
+		t.Helper()
+		out, err := run(t, args...)
+		if err != nil {
+			t.Fatalf("cmd.Execute(%v): %v", args, err)
+		}
+		return out
+	}
+
+	if _, err := run(t, "-"); err == nil || !strings.Contains(err.Error(), "no recent topics yet") {
+		t.Fatalf("expected no recent topics error, got %v", err)
+	}
+	if out := mustRun(t, "recent"); out != "No recent topics yet.\n" {
+		t.Errorf("recent = %q", out)
+	}
+
+	mustRun(t, "http", "get")
+	mustRun(t, "using-k6", "scenarios")
+	mustRun(t, "http")
+	mustRun(t, "http", "get")
+	if _, err := run(t, "http", "nosuchpage-xyz-abc-def"); err == nil {
+		t.Fatal("expected topic not found")
+	}
+	mustRun(t, "--list")
+
+	t.Run("recent", func(t *testing.T) {
+		assertGolden(t, "history/recent.txt", mustRun(t, "recent"))
+	})
+
+	t.Run("last", func(t *testing.T) {
+		if out := mustRun(t, "-"); out != mustRun(t, "http", "get") {
+			t.Errorf("k6 x docs - did not reopen http get:\n%s", out)
+		}
+	})
+
+	t.Run("bookmarks", func(t *testing.T) {
+		if out := mustRun(t, "bookmark", "list"); !strings.HasPrefix(out, "No bookmarks yet.") {
+			t.Errorf("bookmark list = %q", out)
+		}
+		mustRun(t, "bookmark", "add", "using-k6", "scenarios")
+		mustRun(t, "bookmark", "add", "http", "get")
+		if out := mustRun(t, "bookmark", "add", "http", "get"); out != "Already bookmarked: http get\n" {
+			t.Errorf("second bookmark add = %q", out)
+		}
+		assertGolden(t, "history/bookmarks.txt", mustRun(t, "bookmark", "list"))
+
+		if out := mustRun(t, "bookmark", "rm", "http", "get"); out != "Removed bookmark http get\n" {
+			t.Errorf("bookmark rm = %q", out)
+		}
+		if _, err := run(t, "bookmark", "rm", "http", "get"); err == nil ||
+			!strings.Contains(err.Error(), "not bookmarked: http get") {
+			t.Errorf("expected not bookmarked error, got %v", err)
+		}
+	})
+}
+
+func TestHistoryNotRecorded(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = "/home/test"
+	gs.Env["NO_COLOR"] = "1"
+
+	run := func(tty bool, args ...string) error {
+		gs.Stdout.IsTTY = tty
+		cmd := newCmd(gs)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "--no-pager"}, args...))
+		return cmd.Execute()
+	}
+
+	// Output that is not a terminal, as for agents and scripts.
+	if err := run(false, "http", "get"); err != nil {
+		t.Fatal(err)
+	}
+	// A lookup whose output fails.
+	if err := run(true, "http", "get", "--heading", "no-such-heading"); err == nil {
+		t.Fatal("expected heading not found")
+	}
+
+	history, err := readSlugList(afs, gs.Env, historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Errorf("history = %v, want no entries", history)
+	}
+}
+
+func TestPrintSlugList_CommandArgs(t *testing.T) {
+	t.Parallel()
+
+	idx := &Index{Sections: []Section{{Slug: "javascript-api/jslib", Category: "javascript-api"}}}
+	idx.reindex()
+
+	// "jslib" opens javascript-api/jslib, so the removed page's full slug is printed.
+	var buf bytes.Buffer
+	printSlugList(&buf, idx, []string{"javascript-api/k6-jslib"}, "Bookmarks", "")
+	if !strings.Contains(buf.String(), "javascript-api/k6-jslib  (not in these docs)") {
+		t.Errorf("expected the full slug in:\n%s", buf.String())
+	}
+}
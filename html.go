@@ -0,0 +1,359 @@
+package docs
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// htmlStyle is the stylesheet shared by every exported HTML page.
+const htmlStyle = `body {
+  font-family: system-ui, sans-serif; line-height: 1.5;
+  max-width: 60rem; margin: 0 auto; padding: 1rem;
+}
+nav.breadcrumbs { font-size: 0.9rem; color: #555; }
+pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+code { font-family: ui-monospace, monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25rem 0.5rem; text-align: left; }
+blockquote { border-left: 4px solid #7d64ff; margin: 1rem 0; padding: 0.25rem 1rem; background: #f7f5ff; }
+footer { margin-top: 2rem; font-size: 0.85rem; color: #555; }
+`
+
+// reDocsURL matches the URL of a k6 docs page, capturing its path and
+// anchor.
+var reDocsURL = regexp.MustCompile(`^https://grafana\.com/docs/k6/v[^/]+/([^#]*)(#.*)?$`)
+
+// htmlPath returns the path of a section's page relative to the site root.
+// Pages mirror the docs URL structure, e.g. javascript-api/k6-http/index.html.
+// The site index is index.html.
+func htmlPath(slug string) string {
+	if slug == "" {
+		return "index.html"
+	}
+	return slug + "/index.html"
+}
+
+// htmlRoot returns the relative path from a section's page to the site root.
+func htmlRoot(slug string) string {
+	if slug == "" {
+		return ""
+	}
+	return strings.Repeat("../", strings.Count(slug, "/")+1)
+}
+
+// htmlPipeline returns the pipeline of idx without the stages that turn
+// links into text, so that pages keep them as links.
+func htmlPipeline(idx *Index) *Pipeline {
+	pipe := DefaultPipeline()
+	if idx.pipeline != nil {
+		pipe = idx.pipeline.clone()
+	}
+	for _, name := range []string{"internal-links", "inline-links", "links"} {
+		_ = pipe.Disable(name)
+	}
+	return pipe
+}
+
+// htmlHref returns the function that resolves a link destination on the
+// page at root. Links to docs pages in the index point to their exported
+// page, other web links are kept, and any other destination has no page to
+// point to, so its link is dropped.
+func htmlHref(idx *Index, root string) func(dest string) string {
+	return func(dest string) string {
+		u, web := externalURL(dest)
+		if m := reDocsURL.FindStringSubmatch(u); m != nil {
+			if sec, ok := idx.Lookup(strings.TrimRight(m[1], "/")); ok {
+				return root + htmlPath(sec.Slug) + m[2]
+			}
+		}
+		if web || strings.HasPrefix(u, "#") {
+			return u
+		}
+		return ""
+	}
+}
+
+// writeHTML writes a static HTML site for the index into dir: one page per
+// section linked to its parent and children, an index page with the full
+// tree, and a stylesheet. Pages only use relative links, so the folder can
+// be browsed offline; links between docs pages point to the exported pages.
+func writeHTML(afs fsext.Fs, w io.Writer, idx *Index, dir, cacheDir, version string) error {
+	write := func(rel, data string) error {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := afs.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			return fmt.Errorf("create output dir: %w", err)
+		}
+		if err := fsext.WriteFile(afs, p, []byte(data), 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", p, err)
+		}
+		return nil
+	}
+
+	if err := write("style.css", htmlStyle); err != nil {
+		return err
+	}
+	if err := write(htmlPath(""), htmlIndexPage(idx, version)); err != nil {
+		return err
+	}
+
+	var (
+		count int
+		err   error
+		pipe  = htmlPipeline(idx)
+	)
+	idx.Walk(func(sec *Section, _ int) {
+		if err != nil {
+			return
+		}
+		content := pipe.Apply(readMarkdown(afs, cacheDir, sec), version)
+		err = write(htmlPath(sec.Slug), htmlPage(idx, sec, content, version))
+		count++
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "Wrote %d HTML pages to %s\n", count+1, dir)
+	return nil
+}
+
+// htmlDocument wraps body in a page with the given title and stylesheet.
+func htmlDocument(title, root, body string) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	_, _ = fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(title))
+	_, _ = fmt.Fprintf(&sb, "<link rel=\"stylesheet\" href=\"%sstyle.css\">\n", root)
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(body)
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// htmlLink returns an anchor from the page at root to the section's page.
+func htmlLink(root string, sec *Section) string {
+	return fmt.Sprintf("<a href=\"%s%s\">%s</a>", root, htmlPath(sec.Slug), html.EscapeString(sec.Title))
+}
+
+// htmlIndexPage renders the site index as a nested list of every section.
+func htmlIndexPage(idx *Index, version string) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "<main>\n<h1>k6 Documentation (%s)</h1>\n", html.EscapeString(version))
+
+	// Walk is depth-first, so depth grows by at most one per section. Each
+	// item stays open until its children's list is closed.
+	depth := -1
+	idx.Walk(func(sec *Section, d int) {
+		if d > depth {
+			sb.WriteString("<ul>\n")
+		} else {
+			sb.WriteString("</li>\n")
+		}
+		for ; depth > d; depth-- {
+			sb.WriteString("</ul>\n</li>\n")
+		}
+		depth = d
+		_, _ = fmt.Fprintf(&sb, "<li>%s", htmlLink("", sec))
+	})
+	for ; depth >= 0; depth-- {
+		sb.WriteString("</li>\n</ul>\n")
+	}
+	sb.WriteString("</main>\n")
+
+	return htmlDocument("k6 Documentation ("+version+")", "", sb.String())
+}
+
+// htmlPage renders a section's transformed markdown as an HTML page with
+// breadcrumbs back to the index and links to its children. The footer links
+// to the page on grafana.com, which overlay sections do not have.
+func htmlPage(idx *Index, sec *Section, content, version string) string {
+	root := htmlRoot(sec.Slug)
+	var sb strings.Builder
+
+	crumbs := []string{fmt.Sprintf("<a href=\"%s%s\">k6 docs</a>", root, htmlPath(""))}
+	var parents []string
+	for p, ok := parentSection(idx, sec.Slug); ok; p, ok = parentSection(idx, p.Slug) {
+		parents = append([]string{htmlLink(root, p)}, parents...)
+	}
+	crumbs = append(crumbs, parents...)
+	crumbs = append(crumbs, html.EscapeString(sec.Title))
+	_, _ = fmt.Fprintf(&sb, "<nav class=\"breadcrumbs\">%s</nav>\n", strings.Join(crumbs, " › "))
+
+	sb.WriteString("<main>\n")
+	href := htmlHref(idx, root)
+	for _, b := range splitBlocks(content) {
+		sb.WriteString(htmlBlock(b, href))
+	}
+	sb.WriteString("</main>\n")
+
+	if children := idx.Children(sec.Slug); len(children) > 0 {
+		sb.WriteString("<nav class=\"children\">\n<h2>In this section</h2>\n<ul>\n")
+		for _, child := range children {
+			_, _ = fmt.Fprintf(&sb, "<li>%s", htmlLink(root, child))
+			if d := strings.TrimSpace(child.Description); d != "" {
+				_, _ = fmt.Fprintf(&sb, ": %s", html.EscapeString(d))
+			}
+			sb.WriteString("</li>\n")
+		}
+		sb.WriteString("</ul>\n</nav>\n")
+	}
+
+	if !idx.isOverlay(sec) {
+		url := docsURL(version, sec.Slug)
+		_, _ = fmt.Fprintf(&sb, "<footer>Source: <a href=\"%s\">%s</a></footer>\n", url, url)
+	}
+
+	return htmlDocument(sec.Title+" - k6 docs", root, sb.String())
+}
+
+// htmlBlock renders a single markdown block as HTML, resolving links with
+// href.
+func htmlBlock(b mdBlock, href func(string) string) string {
+	switch b.kind {
+	case blockHeading:
+		level, text := parseHeading(b.text)
+		return fmt.Sprintf("<h%d id=\"%s\">%s</h%d>\n", level, headingAnchor(text), htmlInline(text, href), level)
+	case blockCode:
+		return htmlCode(b.text)
+	case blockTable:
+		return htmlTable(b.text, href)
+	case blockProse:
+	}
+
+	lines := strings.Split(b.text, "\n")
+	switch {
+	case reRule.MatchString(b.text):
+		return "<hr>\n"
+	case strings.HasPrefix(strings.TrimSpace(lines[0]), ">"):
+		text := make([]string, 0, len(lines))
+		for _, l := range lines {
+			text = append(text, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), ">")))
+		}
+		return "<blockquote><p>" + htmlInline(strings.Join(text, " "), href) + "</p></blockquote>\n"
+	case reListItem.MatchString(lines[0]):
+		return htmlList(lines, href)
+	default:
+		return "<p>" + htmlInline(strings.Join(lines, " "), href) + "</p>\n"
+	}
+}
+
+// htmlInline escapes text and converts inline code, links, bold and italic
+// markup. Link destinations are resolved with href; a link it returns no
+// destination for is rendered as its text. Markup inside code spans is left
+// alone.
+func htmlInline(s string, href func(string) string) string {
+	emphasis := func(s string) string {
+		s = reBold.ReplaceAllString(html.EscapeString(s), "<strong>$1</strong>")
+		return reItalic.ReplaceAllString(s, "$1<em>$2</em>")
+	}
+	links := func(s string) string {
+		var sb strings.Builder
+		last := 0
+		for _, m := range reMarkdownLink.FindAllStringSubmatchIndex(s, -1) {
+			if m[0] > 0 && s[m[0]-1] == '!' {
+				continue
+			}
+			sb.WriteString(emphasis(s[last:m[0]]))
+			text := emphasis(s[m[2]:m[3]])
+			if u := href(s[m[4]:m[5]]); u != "" {
+				text = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(u), text)
+			}
+			sb.WriteString(text)
+			last = m[1]
+		}
+		sb.WriteString(emphasis(s[last:]))
+		return sb.String()
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range reInlineCode.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(links(s[last:m[0]]))
+		sb.WriteString("<code>" + html.EscapeString(s[m[2]:m[3]]) + "</code>")
+		last = m[1]
+	}
+	sb.WriteString(links(s[last:]))
+	return sb.String()
+}
+
+// htmlCode renders a fenced code block, tagging it with its language.
+func htmlCode(text string) string {
+	lang := strings.ToLower(fenceLang(strings.SplitN(text, "\n", 2)[0]))
+	lines := codeBody(text)
+
+	class := ""
+	if lang != "" {
+		class = fmt.Sprintf(" class=\"language-%s\"", html.EscapeString(lang))
+	}
+	return fmt.Sprintf("<pre><code%s>%s\n</code></pre>\n", class, html.EscapeString(strings.Join(lines, "\n")))
+}
+
+// htmlList renders list items as an ordered or unordered list. Nested items
+// are flattened; continuation lines join the previous item.
+func htmlList(lines []string, href func(string) string) string {
+	var items []string
+	ordered := false
+	for i, l := range lines {
+		m := reListItem.FindStringSubmatch(l)
+		if m == nil {
+			if len(items) > 0 {
+				items[len(items)-1] += " " + strings.TrimSpace(l)
+			}
+			continue
+		}
+		if i == 0 {
+			ordered = strings.HasSuffix(m[2], ".")
+		}
+		items = append(items, m[3])
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	var sb strings.Builder
+	sb.WriteString("<" + tag + ">\n")
+	for _, item := range items {
+		sb.WriteString("<li>" + htmlInline(item, href) + "</li>\n")
+	}
+	sb.WriteString("</" + tag + ">\n")
+	return sb.String()
+}
+
+// htmlTable renders a markdown table. Rows above the delimiter row are
+// header cells.
+func htmlTable(text string, href func(string) string) string {
+	var sb strings.Builder
+	sb.WriteString("<table>\n")
+
+	var rows [][]string
+	header := -1
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if isDelimiterRow(cells) {
+			header = len(rows) - 1
+			continue
+		}
+		rows = append(rows, cells)
+	}
+
+	for r, row := range rows {
+		cell := "td"
+		if r <= header {
+			cell = "th"
+		}
+		sb.WriteString("<tr>")
+		for _, c := range row {
+			_, _ = fmt.Fprintf(&sb, "<%s>%s</%s>", cell, htmlInline(c, href), cell)
+		}
+		sb.WriteString("</tr>\n")
+	}
+
+	sb.WriteString("</table>\n")
+	return sb.String()
+}
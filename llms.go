@@ -0,0 +1,64 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// docsURL returns the grafana.com URL of a section for the given docs version.
+func docsURL(version, slug string) string {
+	return "https://grafana.com/docs/k6/" + version + "/" + slug + "/"
+}
+
+// llmsHeader writes the title and summary shared by llms.txt and llms-full.txt.
+func llmsHeader(w io.Writer, version string) {
+	_, _ = fmt.Fprintf(w, "# k6 Documentation (%s)\n\n", version)
+	_, _ = fmt.Fprintf(w, "> Documentation for k6 %s, the open source load testing tool. "+
+		"Every section can also be read offline with `k6 x docs <topic>`.\n", version)
+}
+
+// printLLMs writes an llms.txt index: one link per section with its
+// description and the k6 x docs command that prints it, grouped by
+// top-level category. Overlay sections have no page on grafana.com, so they
+// are listed without a link.
+func printLLMs(w io.Writer, idx *Index, version string) {
+	llmsHeader(w, version)
+
+	idx.Walk(func(sec *Section, depth int) {
+		if depth == 0 {
+			_, _ = fmt.Fprintf(w, "\n## %s\n\n", sec.Title)
+		}
+		line := fmt.Sprintf("- [%s](%s)", sec.Title, docsURL(version, sec.Slug))
+		if idx.isOverlay(sec) {
+			line = "- " + sec.Title
+		}
+		if sec.Description != "" {
+			line += ": " + strings.TrimSpace(sec.Description)
+		}
+		_, _ = fmt.Fprintf(w, "%s `k6 x docs %s`\n", line, commandArgs(idx, sec.Slug))
+	})
+}
+
+// printLLMsFull writes llms-full.txt: the transformed content of every
+// section, in index order, each preceded by its source URL and command.
+// Overlay sections only have the command.
+func printLLMsFull(afs fsext.Fs, w io.Writer, idx *Index, cacheDir, version string) {
+	llmsHeader(w, version)
+
+	idx.Walk(func(sec *Section, _ int) {
+		content := readAndTransform(afs, idx, cacheDir, sec, version)
+		if content == "" {
+			return
+		}
+		_, _ = fmt.Fprint(w, "\n---\n\n")
+		if !idx.isOverlay(sec) {
+			_, _ = fmt.Fprintf(w, "Source: %s\n", docsURL(version, sec.Slug))
+		}
+		_, _ = fmt.Fprintf(w, "Command: k6 x docs %s\n\n", commandArgs(idx, sec.Slug))
+		_, _ = fmt.Fprint(w, strings.TrimSpace(content))
+		_, _ = fmt.Fprintln(w)
+	})
+}
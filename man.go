@@ -0,0 +1,295 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// manSection is the man page section docs are exported to: miscellaneous
+// documentation, like other non-command reference pages.
+const manSection = "7"
+
+// manName returns the man page name of a section, e.g. k6-docs-http-get.
+// The top-level index page is k6-docs.
+func manName(idx *Index, slug string) string {
+	if slug == "" {
+		return "k6-docs"
+	}
+	args := commandArgs(idx, slug)
+	return "k6-docs-" + strings.NewReplacer("/", "-", " ", "-").Replace(args)
+}
+
+// parentSection returns the section whose slug is the parent path of slug.
+func parentSection(idx *Index, slug string) (*Section, bool) {
+	parent := path.Dir(slug)
+	if parent == "." {
+		return nil, false
+	}
+	return idx.Lookup(parent)
+}
+
+// writeMan writes a man page for every section into dir/man7, plus a k6-docs
+// index page, so that MANPATH=dir man k6-docs-http-get works. It fails if
+// two sections map to the same man page name rather than let one page
+// overwrite the other.
+func writeMan(afs fsext.Fs, w io.Writer, idx *Index, dir, cacheDir, version string) error {
+	manDir := filepath.Join(dir, "man"+manSection)
+	if err := afs.MkdirAll(manDir, 0o750); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	write := func(name, page string) error {
+		p := filepath.Join(manDir, name+"."+manSection)
+		if err := fsext.WriteFile(afs, p, []byte(page), 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", p, err)
+		}
+		return nil
+	}
+
+	if err := write(manName(idx, ""), manIndexPage(idx, version)); err != nil {
+		return err
+	}
+
+	var (
+		count int
+		err   error
+		// written maps the man page names written so far to their slugs.
+		written = map[string]string{manName(idx, ""): "the index page"}
+	)
+	idx.Walk(func(sec *Section, _ int) {
+		if err != nil {
+			return
+		}
+		name := manName(idx, sec.Slug)
+		if other, ok := written[name]; ok {
+			err = fmt.Errorf("man page %s of %s would overwrite the page of %s", name, sec.Slug, other)
+			return
+		}
+		written[name] = sec.Slug
+		content := readAndTransform(afs, idx, cacheDir, sec, version)
+		err = write(name, manPage(idx, sec, content, version))
+		count++
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "Wrote %d man pages to %s\n", count+1, manDir)
+	return nil
+}
+
+// manHeader writes the .TH title line and the NAME section.
+func manHeader(sb *strings.Builder, name, summary, version string) {
+	_, _ = fmt.Fprintf(sb, ".TH %q %s \"\" \"k6 %s\" \"k6 Documentation\"\n",
+		strings.ToUpper(name), manSection, version)
+	sb.WriteString(".SH NAME\n")
+	_, _ = fmt.Fprintf(sb, "%s \\- %s\n", name, roffEscape(summary))
+}
+
+// manIndexPage renders the k6-docs page listing every section.
+func manIndexPage(idx *Index, version string) string {
+	var sb strings.Builder
+	manHeader(&sb, manName(idx, ""), "k6 documentation index", version)
+	sb.WriteString(".SH SYNOPSIS\n.B k6 x docs\n.I topic\n")
+	sb.WriteString(".SH DESCRIPTION\nOffline documentation for k6, one page per topic.\n")
+	sb.WriteString(".SH TOPICS\n")
+	idx.Walk(func(sec *Section, depth int) {
+		entry := fmt.Sprintf(".TP\n.BR %s (%s)\n%s\n", manName(idx, sec.Slug), manSection, roffEscape(sec.Title))
+		if depth > 0 {
+			entry = fmt.Sprintf(".RS %d\n%s.RE\n", depth*2, entry)
+		}
+		sb.WriteString(entry)
+	})
+	return sb.String()
+}
+
+// manPage renders a section's transformed markdown as a roff man page,
+// ending with its grafana.com URL unless it comes from an overlay.
+func manPage(idx *Index, sec *Section, content, version string) string {
+	var sb strings.Builder
+
+	summary := sec.Title
+	if d := strings.TrimSpace(sec.Description); d != "" {
+		summary += " - " + d
+	}
+	manHeader(&sb, manName(idx, sec.Slug), summary, version)
+	_, _ = fmt.Fprintf(&sb, ".SH SYNOPSIS\n.B k6 x docs %s\n", roffEscape(commandArgs(idx, sec.Slug)))
+	sb.WriteString(".SH DESCRIPTION\n")
+
+	for _, b := range splitBlocks(content) {
+		sb.WriteString(roffBlock(b))
+	}
+
+	var refs []string
+	if parent, ok := parentSection(idx, sec.Slug); ok {
+		refs = append(refs, manName(idx, parent.Slug))
+	}
+	for _, child := range idx.Children(sec.Slug) {
+		refs = append(refs, manName(idx, child.Slug))
+	}
+	sb.WriteString(".SH SEE ALSO\n")
+	for i, ref := range refs {
+		sep := ","
+		if i == len(refs)-1 {
+			sep = ""
+		}
+		_, _ = fmt.Fprintf(&sb, ".BR %s (%s)%s\n", ref, manSection, sep)
+	}
+	if !idx.isOverlay(sec) {
+		_, _ = fmt.Fprintf(&sb, ".PP\n%s\n", roffEscape(docsURL(version, sec.Slug)))
+	}
+
+	return sb.String()
+}
+
+// roffBlock renders a single markdown block as roff requests.
+func roffBlock(b mdBlock) string {
+	switch b.kind {
+	case blockHeading:
+		level, text := parseHeading(b.text)
+		switch level {
+		case 1:
+			// The page title is already in NAME.
+			return ""
+		case 2:
+			return ".SH " + roffInline(strings.ToUpper(text)) + "\n"
+		default:
+			return ".SS " + roffInline(text) + "\n"
+		}
+	case blockCode:
+		return roffCode(b.text)
+	case blockTable:
+		return roffTable(b.text)
+	case blockProse:
+	}
+
+	lines := strings.Split(b.text, "\n")
+	switch {
+	case reRule.MatchString(b.text):
+		return ".PP\n"
+	case strings.HasPrefix(strings.TrimSpace(lines[0]), ">"):
+		return roffQuote(lines)
+	case reListItem.MatchString(lines[0]):
+		return roffList(lines)
+	default:
+		return ".PP\n" + roffLine(roffInline(strings.Join(lines, " "))) + "\n"
+	}
+}
+
+// roffEscape escapes backslashes so text is printed literally.
+func roffEscape(s string) string {
+	return strings.ReplaceAll(s, `\`, `\e`)
+}
+
+// roffLine guards a text line that would otherwise be read as a request.
+func roffLine(s string) string {
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		return `\&` + s
+	}
+	return s
+}
+
+// roffInline escapes text and converts inline code and bold to bold, and
+// italic to italic. Emphasis inside code spans is left alone.
+func roffInline(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range reInlineCode.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(roffEmphasis(roffEscape(s[last:m[0]])))
+		sb.WriteString(`\fB` + roffEscape(s[m[2]:m[3]]) + `\fR`)
+		last = m[1]
+	}
+	sb.WriteString(roffEmphasis(roffEscape(s[last:])))
+	return sb.String()
+}
+
+// roffEmphasis converts bold and italic markup to roff font changes.
+func roffEmphasis(s string) string {
+	s = reBold.ReplaceAllString(s, `\fB$1\fR`)
+	return reItalic.ReplaceAllString(s, `$1\fI$2\fR`)
+}
+
+// roffCode renders a fenced code block as indented literal text.
+func roffCode(text string) string {
+	lines := codeBody(text)
+
+	var sb strings.Builder
+	sb.WriteString(".PP\n.RS 4\n.nf\n")
+	for _, l := range lines {
+		sb.WriteString(roffLine(roffEscape(l)) + "\n")
+	}
+	sb.WriteString(".fi\n.RE\n")
+	return sb.String()
+}
+
+// roffQuote renders a blockquote as an indented paragraph. A leading
+// "**Title:**", as produced for admonitions, stays bold.
+func roffQuote(lines []string) string {
+	text := make([]string, 0, len(lines))
+	for _, l := range lines {
+		text = append(text, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), ">")))
+	}
+	return ".RS 4\n.PP\n" + roffLine(roffInline(strings.Join(text, " "))) + "\n.RE\n"
+}
+
+// roffList renders list items as tagged paragraphs with bullets or numbers.
+func roffList(lines []string) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		m := reListItem.FindStringSubmatch(l)
+		if m == nil {
+			// Continuation of the previous item.
+			sb.WriteString(roffLine(roffInline(strings.TrimSpace(l))) + "\n")
+			continue
+		}
+		marker := m[2]
+		if !strings.HasSuffix(marker, ".") {
+			marker = `\(bu`
+		}
+		_, _ = fmt.Fprintf(&sb, ".IP %s %d\n%s\n", marker, 4+len(m[1]), roffLine(roffInline(m[3])))
+	}
+	return sb.String()
+}
+
+// roffTable renders a table as aligned literal text, so pages do not depend
+// on the tbl preprocessor.
+func roffTable(text string) string {
+	var rows [][]string
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if isDelimiterRow(cells) {
+			continue
+		}
+		for i, c := range cells {
+			cells[i] = reInlineCode.ReplaceAllString(c, "$1")
+		}
+		rows = append(rows, cells)
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, c := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], visibleWidth(c))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(".PP\n.RS 4\n.nf\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = padRight(c, widths[i])
+		}
+		sb.WriteString(roffLine(roffEscape(strings.TrimRight(strings.Join(cells, "  "), " "))) + "\n")
+	}
+	sb.WriteString(".fi\n.RE\n")
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package docs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// reDeprecatedNote matches an admonition saying that a page is
+	// deprecated, or that its module was removed.
+	reDeprecatedNote = regexp.MustCompile(
+		`(?i)\b(?:is|are|was|were|been|now)\s+(?:deprecated|removed)\b|\bdeprecated\s+(?:since|in|as of)\b`,
+	)
+	// rePromotedNote matches an admonition saying that a module was promoted
+	// out of k6/experimental. Only the experimental page is replaced: the
+	// page of the module it became says the same thing.
+	rePromotedNote = regexp.MustCompile(`(?i)\b(?:is|are|was|were|been|now)\s+(?:promoted|graduated)\b`)
+	// reExperimentalNote matches an admonition saying that a page is
+	// experimental.
+	reExperimentalNote = regexp.MustCompile(
+		`(?i)\bexperimental\s+(?:module|feature|api)\b|\b(?:is|are)\s+(?:still\s+)?(?:an?\s+)?experimental\b`,
+	)
+	// reSince matches "available since v0.52.0", "introduced in k6 v1.1"
+	// or "starting with k6 v0.50". Captures: [1] or [2]=version
+	reSince = regexp.MustCompile(
+		`(?i)\b(?:available|introduced|added|supported)\s+(?:since|in|from|as of)\s+(?:k6\s+)?v?(\d+\.\d+(?:\.\d+)?)\b` +
+			`|\b(?:starting|beginning)\s+(?:with|from|in)\s+k6\s+v?(\d+\.\d+(?:\.\d+)?)\b`,
+	)
+)
+
+// Markers are availability hints for a section: whether it is deprecated or
+// experimental, and the k6 version it is available since.
+type Markers struct {
+	Deprecated   bool   `json:"deprecated,omitempty"`
+	Experimental bool   `json:"experimental,omitempty"`
+	Since        string `json:"since,omitempty"`
+}
+
+// DetectMarkers extracts the markers of a page from its frontmatter, the
+// admonitions in content, and its slug: pages under k6/experimental are
+// experimental. Only admonitions are searched, since the rest of a page
+// often mentions the versions and deprecations of other features.
+func DetectMarkers(slug, content string, fm Frontmatter) Markers {
+	experimentalPage := strings.HasPrefix(slug, "javascript-api/k6-experimental")
+	m := Markers{
+		Deprecated:   fm.Deprecated,
+		Experimental: fm.Experimental || experimentalPage,
+		Since:        fm.Since,
+	}
+
+	prose, _ := maskCode(content)
+	for _, a := range reAdmonition.FindAllStringSubmatch(prose, -1) {
+		body := a[2]
+		m.Deprecated = m.Deprecated || reDeprecatedNote.MatchString(body) ||
+			experimentalPage && rePromotedNote.MatchString(body)
+		m.Experimental = m.Experimental || reExperimentalNote.MatchString(body)
+		if s := reSince.FindStringSubmatch(body); m.Since == "" && s != nil {
+			m.Since = s[1] + s[2]
+		}
+	}
+	if m.Since != "" && !strings.HasPrefix(m.Since, "v") {
+		m.Since = "v" + m.Since
+	}
+	return m
+}
+
+// badges returns the markers of sec as a prefix for its description, e.g.
+// "[deprecated] ".
+func badges(sec *Section) string {
+	var b strings.Builder
+	if sec.Deprecated {
+		b.WriteString("[deprecated] ")
+	}
+	if sec.Experimental {
+		b.WriteString("[experimental] ")
+	}
+	if sec.Since != "" {
+		b.WriteString("[since " + sec.Since + "] ")
+	}
+	return b.String()
+}
+
+// sectionFilter selects the sections listed by the TOC, topic lists and
+// search results.
+type sectionFilter struct {
+	hideDeprecated   bool
+	experimentalOnly bool
+}
+
+// match reports whether sec is listed.
+func (f sectionFilter) match(sec *Section) bool {
+	return (!f.hideDeprecated || !sec.Deprecated) && (!f.experimentalOnly || sec.Experimental)
+}
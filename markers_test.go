@@ -0,0 +1,152 @@
+package docs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestDetectMarkers(t *testing.T) {
+	t.Parallel()
+
+	admonition := func(body string) string {
+		return "{{< admonition type=\"caution\" >}}\n\n" + body + "\n\n{{< /admonition >}}\n"
+	}
+
+	tests := []struct {
+		name    string
+		slug    string
+		content string
+		fm      Frontmatter
+		want    Markers
+	}{
+		{name: "none", slug: "using-k6/checks", content: "Checks validate conditions.\n"},
+		{
+			name:    "deprecated admonition",
+			slug:    "javascript-api/k6-ws",
+			content: admonition("This module is deprecated. Use k6/websockets instead."),
+			want:    Markers{Deprecated: true},
+		},
+		{
+			name:    "promoted experimental module",
+			slug:    "javascript-api/k6-experimental/browser",
+			content: admonition("The browser module has been promoted to k6/browser."),
+			want:    Markers{Deprecated: true, Experimental: true},
+		},
+		{
+			name:    "promotion on the page of the new module is ignored",
+			slug:    "javascript-api/k6-browser",
+			content: admonition("The browser module has been promoted from k6/experimental/browser."),
+		},
+		{
+			name:    "experimental admonition",
+			slug:    "javascript-api/k6-net-grpc/stream",
+			content: admonition("Streaming is an experimental feature."),
+			want:    Markers{Experimental: true},
+		},
+		{
+			name:    "deprecation outside admonitions is ignored",
+			slug:    "javascript-api/k6-websockets",
+			content: "This module replaces k6/ws, which is deprecated.\n",
+		},
+		{
+			name:    "since",
+			slug:    "using-k6/tags",
+			content: admonition("Tags are available since k6 v0.52.0."),
+			want:    Markers{Since: "v0.52.0"},
+		},
+		{
+			name:    "since without v",
+			slug:    "using-k6/tags",
+			content: admonition("Starting with k6 1.1, tags are copied."),
+			want:    Markers{Since: "v1.1"},
+		},
+		{
+			name:    "since outside admonitions is ignored",
+			slug:    "using-k6/tags",
+			content: "Tags are available since k6 v0.52.0.\n",
+		},
+		{
+			name:    "code is not searched",
+			slug:    "examples/old",
+			content: "```js\n// {{< admonition type=\"note\" >}} is deprecated {{< /admonition >}}\n// available since v9.9\n```\n",
+		},
+		{
+			name: "frontmatter",
+			slug: "internal/client",
+			fm:   Frontmatter{Deprecated: true, Since: "2.0"},
+			want: Markers{Deprecated: true, Since: "v2.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := DetectMarkers(tt.slug, tt.content, tt.fm); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkerBadgesAndFilters(t *testing.T) {
+	t.Parallel()
+
+	sections := []Section{
+		{Slug: "javascript-api", Title: "JavaScript API", Category: "javascript-api", IsIndex: true},
+		{
+			Slug: "javascript-api/k6-http", Title: "k6/http", Description: "HTTP requests.",
+			Category: "javascript-api", Weight: 1,
+		},
+		{
+			Slug: "javascript-api/k6-ws", Title: "k6/ws", Description: "WebSocket client.",
+			Category: "javascript-api", Weight: 2, Markers: Markers{Deprecated: true},
+		},
+		{
+			Slug: "javascript-api/k6-experimental", Title: "k6/experimental", Description: "Experimental modules.",
+			Category: "javascript-api", Weight: 3, Markers: Markers{Experimental: true, Since: "v0.40.0"},
+		},
+		{Slug: "using-k6", Title: "Using k6", Category: "using-k6", IsIndex: true, Weight: 1},
+		{Slug: "using-k6/checks", Title: "Checks", Description: "Checks.", Category: "using-k6"},
+	}
+	PopulateChildren(sections)
+	idx := &Index{Sections: sections}
+	idx.reindex()
+
+	render := func(filter sectionFilter) (string, string, string) {
+		idx.filter = filter
+		var toc, list, search bytes.Buffer
+		printTOC(&toc, idx, "v1.0.0")
+		printList(&list, idx, "javascript-api")
+		printSearch(fsext.NewMemMapFs(), &search, idx, "k6/", "/cache", "v1.0.0", 0)
+		return toc.String(), list.String(), search.String()
+	}
+
+	toc, list, search := render(sectionFilter{})
+	for _, want := range []string{"[deprecated] WebSocket client.", "[experimental] [since v0.40.0] Experimental modules."} {
+		for name, out := range map[string]string{"toc": toc, "list": list, "search": search} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: missing %q:\n%s", name, want, out)
+			}
+		}
+	}
+
+	toc, list, search = render(sectionFilter{hideDeprecated: true})
+	for name, out := range map[string]string{"toc": toc, "list": list, "search": search} {
+		if strings.Contains(out, "k6/ws") || strings.Contains(out, "WebSocket") {
+			t.Errorf("%s: deprecated topic listed:\n%s", name, out)
+		}
+	}
+
+	toc, list, search = render(sectionFilter{experimentalOnly: true})
+	for name, out := range map[string]string{"toc": toc, "list": list, "search": search} {
+		if !strings.Contains(out, "experimental") || strings.Contains(out, "HTTP requests") {
+			t.Errorf("%s: expected only experimental topics:\n%s", name, out)
+		}
+	}
+	if strings.Contains(toc, "Using k6") {
+		t.Errorf("toc: category without experimental topics listed:\n%s", toc)
+	}
+}
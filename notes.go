@@ -0,0 +1,367 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// defaultEditor is used by note edit when neither $VISUAL nor $EDITOR is set.
+const defaultEditor = "vi"
+
+// errInvalidNoteTopic is returned for topics that cannot name a notes file.
+var errInvalidNoteTopic = errors.New("invalid note topic")
+
+// notesDir returns the directory user notes are stored in, one markdown file
+// per section slug. Notes live outside the versioned docs cache so that they
+// apply to every docs version. The layout is ~/.local/share/k6/docs-notes/.
+func notesDir(env map[string]string) (string, error) {
+	dir, err := dataDir(env)
+	if err != nil {
+		return "", fmt.Errorf("notes dir: %w", err)
+	}
+	return filepath.Join(dir, "docs-notes"), nil
+}
+
+// notePath returns the file that holds the notes for slug. Slugs come from
+// the command line when a topic does not resolve, so slugs that would name
+// a file outside the notes directory are rejected.
+func notePath(env map[string]string, slug string) (string, error) {
+	dir, err := notesDir(env)
+	if err != nil {
+		return "", err
+	}
+
+	isSep := func(r rune) bool { return r == '/' || r == '\\' }
+	if slug == "" || isSep(rune(slug[0])) || filepath.IsAbs(slug) ||
+		slices.Contains(strings.FieldsFunc(slug, isSep), "..") {
+		return "", fmt.Errorf("%w: %q", errInvalidNoteTopic, slug)
+	}
+	path := filepath.Join(dir, filepath.FromSlash(slug)+".md")
+	if rel, err := filepath.Rel(dir, path); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %q", errInvalidNoteTopic, slug)
+	}
+	return path, nil
+}
+
+// readNotes returns the user's notes for slug, or "" if there are none.
+func readNotes(afs fsext.Fs, env map[string]string, slug string) string {
+	path, err := notePath(env, slug)
+	if err != nil {
+		return ""
+	}
+	data, err := fsext.ReadFile(afs, path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// addNote appends text as a new paragraph to the notes for slug.
+func addNote(afs fsext.Fs, env map[string]string, slug, text string) error {
+	path, err := notePath(env, slug)
+	if err != nil {
+		return err
+	}
+	content := strings.TrimSpace(text) + "\n"
+	if existing := readNotes(afs, env, slug); existing != "" {
+		content = existing + "\n\n" + content
+	}
+	if err := afs.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create notes dir: %w", err)
+	}
+	if err := fsext.WriteFile(afs, path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("write note: %w", err)
+	}
+	return nil
+}
+
+// removeNotes deletes the notes for slug. It reports whether there were any.
+func removeNotes(afs fsext.Fs, env map[string]string, slug string) (bool, error) {
+	path, err := notePath(env, slug)
+	if err != nil {
+		return false, err
+	}
+	if err := afs.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("remove notes: %w", err)
+	}
+	return true, nil
+}
+
+// noteSlugs returns the slugs that have notes, sorted.
+func noteSlugs(afs fsext.Fs, env map[string]string) ([]string, error) {
+	dir, err := notesDir(env)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := afs.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	var slugs []string
+	err = fsext.Walk(afs, dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		slugs = append(slugs, filepath.ToSlash(strings.TrimSuffix(rel, ".md")))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	sort.Strings(slugs)
+	return slugs, nil
+}
+
+// printNotes appends the user's notes for a section in a marked block.
+func printNotes(w io.Writer, idx *Index, notes, slug string) {
+	if notes == "" {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "---")
+	_, _ = fmt.Fprintln(w, "## Your notes")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, notes)
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "Edit: k6 x docs note edit %s\n", commandArgs(idx, slug))
+}
+
+func newNoteCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "note",
+		Short: "Attach personal notes to doc topics",
+		Long: "Keep your own notes on doc topics. Notes are stored per topic, independent of the\n" +
+			"docs version, and are shown at the end of the topic.",
+		Example: "  k6 x docs note add http get -m \"Our proxy strips the X-Trace header\"\n" +
+			"  k6 x docs note edit http get\n  k6 x docs note list",
+	}
+
+	var message string
+	addCmd := &cobra.Command{
+		Use:   "add <topic> [subtopic...]",
+		Short: "Add a note to a topic (text from -m or stdin)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNoteAdd(gs, cmd, opts, args, message)
+		},
+	}
+	addCmd.Flags().StringVarP(&message, "message", "m", "", "Note text (read from stdin when empty)")
+
+	editCmd := &cobra.Command{
+		Use:   "edit <topic> [subtopic...]",
+		Short: "Edit the notes of a topic in $VISUAL or $EDITOR",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNoteEdit(gs, cmd, opts, args)
+		},
+	}
+
+	rmCmd := &cobra.Command{
+		Use:   "rm <topic> [subtopic...]",
+		Short: "Remove the notes of a topic",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNoteRm(gs, cmd, opts, args)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [topic] [subtopic...]",
+		Short: "List topics with notes, or print the notes of a topic",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNoteList(gs, cmd, opts, args)
+		},
+	}
+
+	cmd.AddCommand(addCmd, editCmd, rmCmd, listCmd)
+	return cmd
+}
+
+// noteTopic resolves the slug a note command refers to, and returns it with
+// the docs index. A topic that no longer exists in the docs still resolves
+// when it has notes, so that notes on removed pages can be listed and
+// removed.
+func noteTopic(
+	gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string,
+) (*Index, string, error) {
+	_, _, idx, err := setup(gs, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	slug := idx.resolve(args)
+	if _, ok := idx.Lookup(slug); ok {
+		return idx, slug, nil
+	}
+	hasNotes := func(s string) bool { return readNotes(gs.FS, gs.Env, s) != "" }
+	if slug := ResolveWithLookup(args, hasNotes); hasNotes(slug) {
+		return idx, slug, nil
+	}
+
+	sec, err := resolveFuzzy(cmd.ErrOrStderr(), idx, args)
+	if err != nil {
+		return nil, "", err
+	}
+	return idx, sec.Slug, nil
+}
+
+func runNoteAdd(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string, message string) error {
+	idx, slug, err := noteTopic(gs, cmd, opts, args)
+	if err != nil {
+		return err
+	}
+
+	if message == "" && gs.Stdin != nil {
+		data, err := io.ReadAll(io.LimitReader(gs.Stdin, maxFileSize))
+		if err != nil {
+			return fmt.Errorf("read note: %w", err)
+		}
+		message = string(data)
+	}
+	if strings.TrimSpace(message) == "" {
+		return errors.New("note is empty; pass the text with -m or on stdin")
+	}
+
+	if err := addNote(gs.FS, gs.Env, slug, message); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added note to %s\n", commandArgs(idx, slug))
+	return nil
+}
+
+func runNoteEdit(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string) error {
+	_, slug, err := noteTopic(gs, cmd, opts, args)
+	if err != nil {
+		return err
+	}
+	path, err := notePath(gs.Env, slug)
+	if err != nil {
+		return err
+	}
+	if _, err := gs.FS.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if err := gs.FS.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return fmt.Errorf("create notes dir: %w", err)
+		}
+		if err := fsext.WriteFile(gs.FS, path, nil, 0o600); err != nil {
+			return fmt.Errorf("write note: %w", err)
+		}
+	}
+
+	editor := gs.Env["VISUAL"]
+	if strings.TrimSpace(editor) == "" {
+		editor = gs.Env["EDITOR"]
+	}
+	parts := strings.Fields(editor)
+	if len(parts) == 0 {
+		parts = []string{defaultEditor}
+	}
+	ec := exec.CommandContext(cmd.Context(), parts[0], append(parts[1:], path)...) //nolint:gosec // user's editor
+	ec.Stdin = gs.Stdin
+	ec.Stdout = gs.Stdout
+	ec.Stderr = gs.Stderr
+	if err := ec.Run(); err != nil {
+		return fmt.Errorf("run editor %s: %w", parts[0], err)
+	}
+
+	// An emptied file removes the notes of the topic.
+	if readNotes(gs.FS, gs.Env, slug) == "" {
+		_, err := removeNotes(gs.FS, gs.Env, slug)
+		return err
+	}
+	return nil
+}
+
+func runNoteRm(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string) error {
+	idx, slug, err := noteTopic(gs, cmd, opts, args)
+	if err != nil {
+		return err
+	}
+	removed, err := removeNotes(gs.FS, gs.Env, slug)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("no notes for %s", commandArgs(idx, slug))
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed notes from %s\n", commandArgs(idx, slug))
+	return nil
+}
+
+func runNoteList(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string) error {
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+	cfg, cfgErr := loadConfig(gs.FS, gs.Env)
+	if cfgErr != nil {
+		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
+	}
+
+	if len(args) > 0 {
+		idx, slug, err := noteTopic(gs, cmd, opts, args)
+		if err != nil {
+			return err
+		}
+		notes := readNotes(gs.FS, gs.Env, slug)
+		if notes == "" {
+			return fmt.Errorf("no notes for %s", commandArgs(idx, slug))
+		}
+		w, render := newOutput(gs, cmd, cfg, opts)
+		_, _ = fmt.Fprintln(w, notes)
+		return render()
+	}
+
+	slugs, err := noteSlugs(gs.FS, gs.Env)
+	if err != nil {
+		return err
+	}
+	var idx *Index
+	if len(slugs) > 0 {
+		if _, _, idx, err = setup(gs, opts); err != nil {
+			return err
+		}
+	}
+	w, render := newOutput(gs, cmd, cfg, opts)
+	printNoteList(w, gs.FS, gs.Env, idx, slugs)
+	return render()
+}
+
+// printNoteList lists the topics with notes and the first line of each.
+func printNoteList(w io.Writer, afs fsext.Fs, env map[string]string, idx *Index, slugs []string) {
+	if len(slugs) == 0 {
+		_, _ = fmt.Fprintln(w, "No notes yet. Add one with: k6 x docs note add <topic> -m <text>")
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "Your notes")
+	_, _ = fmt.Fprintln(w, "Use: k6 x docs note list <topic>")
+	_, _ = fmt.Fprintln(w)
+
+	items := make([]listItem, 0, len(slugs))
+	for _, slug := range slugs {
+		first, _, _ := strings.Cut(readNotes(afs, env, slug), "\n")
+		items = append(items, listItem{Name: commandArgs(idx, slug), Description: truncate(first, 60)})
+	}
+	printAlignedList(w, items)
+}
@@ -0,0 +1,212 @@
+package docs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestNotes(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = "/home/test"
+
+	run := func(t *testing.T, stdin string, args ...string) (string, error) {
+		t.Helper()
+		gs.Stdin = strings.NewReader(stdin)
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x"}, args...))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+	mustRun := func(t *testing.T, stdin string, args ...string) string {
+		t.Helper()
+		out, err := run(t, stdin, args...)
+		if err != nil {
+			t.Fatalf("cmd.Execute(%v): %v", args, err)
+		}
+		return out
+	}
+
+	if out, _ := run(t, "", "note", "list"); !strings.HasPrefix(out, "No notes yet.") {
+		t.Errorf("expected empty notes list, got:\n%s", out)
+	}
+
+	mustRun(t, "", "note", "add", "http", "get", "-m", "http.get ignores X-Trace in our proxy setup.")
+	mustRun(t, "Always set a timeout.\n", "note", "add", "http", "get")
+	mustRun(t, "", "note", "add", "using-k6", "scenarios", "-m", "Use ramping-arrival-rate for soak tests.")
+
+	got, err := fsext.ReadFile(afs, "/home/test/.local/share/k6/docs-notes/javascript-api/k6-http/get.md")
+	if err != nil {
+		t.Fatalf("read note file: %v", err)
+	}
+	if want := "http.get ignores X-Trace in our proxy setup.\n\nAlways set a timeout.\n"; string(got) != want {
+		t.Errorf("note file = %q, want %q", got, want)
+	}
+
+	t.Run("section", func(t *testing.T) {
+		out := mustRun(t, "", "http", "get")
+		want := "---\n## Your notes\n\nhttp.get ignores X-Trace in our proxy setup.\n\nAlways set a timeout.\n\n" +
+			"Edit: k6 x docs note edit http get\n"
+		if !strings.Contains(out, want) {
+			t.Errorf("expected notes block in:\n%s", out)
+		}
+		if out := mustRun(t, "", "http", "get", "--code"); strings.Contains(out, "Your notes") {
+			t.Errorf("notes should only follow the full section, got:\n%s", out)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		assertGolden(t, "notes/list.txt", mustRun(t, "", "note", "list"))
+		out := mustRun(t, "", "note", "list", "using-k6", "scenarios")
+		if out != "Use ramping-arrival-rate for soak tests.\n" {
+			t.Errorf("note list scenarios = %q", out)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := run(t, "  \n", "note", "add", "http", "get")
+		if err == nil || !strings.Contains(err.Error(), "note is empty") {
+			t.Errorf("expected empty note error, got %v", err)
+		}
+	})
+
+	t.Run("rm", func(t *testing.T) {
+		mustRun(t, "", "note", "rm", "using-k6", "scenarios")
+		if out := mustRun(t, "", "using-k6", "scenarios"); strings.Contains(out, "Your notes") {
+			t.Errorf("notes not removed:\n%s", out)
+		}
+		_, err := run(t, "", "note", "rm", "using-k6", "scenarios")
+		if err == nil || !strings.Contains(err.Error(), "no notes for using-k6 scenarios") {
+			t.Errorf("expected no notes error, got %v", err)
+		}
+	})
+}
+
+func TestNotesOnRemovedTopic(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = "/home/test"
+	if err := addNote(afs, gs.Env, "javascript-api/k6-http/fetch", "Gone in this version."); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newCmd(gs)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "note", "rm", "http", "fetch"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Removed notes from http fetch") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestNoteEdit(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as editor")
+	}
+
+	home := t.TempDir()
+	editor := filepath.Join(home, "editor.sh")
+	script := []byte("#!/bin/sh\necho 'Written in the editor.' >> \"$1\"\n")
+	if err := os.WriteFile(editor, script, 0o755); err != nil { //nolint:forbidigo // the editor runs on the real filesystem
+		t.Fatal(err)
+	}
+
+	gs := newTestGlobalState(t, fsext.NewOsFs())
+	gs.Env["HOME"] = home
+	gs.Env["EDITOR"] = editor
+
+	cmd := newCmd(gs)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cacheDir := filepath.Join("testdata", "cache")
+	cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "note", "edit", "http", "get"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cmd.Execute: %v", err)
+	}
+
+	if got := readNotes(gs.FS, gs.Env, "javascript-api/k6-http/get"); got != "Written in the editor." {
+		t.Errorf("notes = %q", got)
+	}
+}
+
+func TestNotePath_OutsideNotesDir(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{"HOME": "/home/test"}
+	for _, slug := range []string{"", "../../outside", "javascript-api/../../../x", "/etc/passwd", `..\x`, "a/.."} {
+		if path, err := notePath(env, slug); !errors.Is(err, errInvalidNoteTopic) {
+			t.Errorf("notePath(%q) = %q, %v; want errInvalidNoteTopic", slug, path, err)
+		}
+	}
+	if _, err := notePath(env, "javascript-api/k6-http/get"); err != nil {
+		t.Errorf("notePath(javascript-api/k6-http/get): %v", err)
+	}
+
+	// A topic that does not resolve is passed through as a slug when it has
+	// notes; it must not reach files outside the notes directory.
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = "/home/test"
+	outside := "/home/test/.local/share/k6/secret.md"
+	if err := fsext.WriteFile(afs, outside, []byte("keep me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newCmd(gs)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--cache-dir", cacheDir, "--version", "v0.55.x", "note", "rm", "../secret"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for a topic outside the notes directory")
+	}
+	if ok, _ := fsext.Exists(afs, outside); !ok {
+		t.Error("note rm deleted a file outside the notes directory")
+	}
+}
+
+func TestPrintNotes_CommandArgs(t *testing.T) {
+	t.Parallel()
+
+	idx := &Index{Sections: []Section{
+		{Slug: "javascript-api/jslib", Category: "javascript-api"},
+		{Slug: "javascript-api/k6-jslib", Category: "javascript-api"},
+	}}
+	idx.reindex()
+
+	// "jslib" opens javascript-api/jslib, so the full slug is printed.
+	var buf bytes.Buffer
+	printNotes(&buf, idx, "Pin the version.", "javascript-api/k6-jslib")
+	if want := "Edit: k6 x docs note edit javascript-api/k6-jslib\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("missing %q:\n%s", want, buf.String())
+	}
+
+	afs := fsext.NewMemMapFs()
+	env := map[string]string{"HOME": "/home/test"}
+	if err := addNote(afs, env, "javascript-api/k6-jslib", "Pin the version."); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	printNoteList(&buf, afs, env, idx, []string{"javascript-api/k6-jslib"})
+	if !strings.Contains(buf.String(), "javascript-api/k6-jslib") {
+		t.Errorf("expected the full slug in:\n%s", buf.String())
+	}
+}
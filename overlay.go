@@ -0,0 +1,226 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// defaultOverlayCategory is the category overlay docs are merged under when
+// the config does not name one.
+const defaultOverlayCategory = "internal"
+
+// overlayWeight orders overlay categories without their own _index.md after
+// the upstream categories in the table of contents.
+const overlayWeight = 1000
+
+// overlayConfig is a directory of team-local markdown docs, written with the
+// same frontmatter as k6-docs, that is merged into the index at runtime.
+type overlayConfig struct {
+	// Dir is the directory to index. A leading ~ is the home directory and
+	// relative paths are relative to the config directory.
+	Dir string `yaml:"dir"`
+	// Category is the top-level slug the docs are merged under. It defaults
+	// to "internal" and may also name an upstream category.
+	Category string `yaml:"category"`
+	// Title is the category title shown in the table of contents when the
+	// directory has no _index.md. It defaults to the capitalized category.
+	Title string `yaml:"title"`
+}
+
+// overlayDir resolves the configured directory of an overlay.
+func overlayDir(env map[string]string, dir string) (string, error) {
+	if rest, ok := strings.CutPrefix(dir, "~"); ok && (rest == "" || rest[0] == '/' || rest[0] == '\\') {
+		home, err := homeDirFromEnv(env)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, rest), nil
+	}
+	if filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	cfgDir, err := configDir(env)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cfgDir, dir), nil
+}
+
+// loadOverlay indexes the markdown files of an overlay directory. Slugs are
+// derived like in the bundle, under the overlay category; an _index.md at the
+// root of the directory describes the category itself. A file with invalid
+// frontmatter is reported through warn and left out.
+func loadOverlay(afs fsext.Fs, dir, category string, warn func(error)) ([]Section, error) {
+	var sections []Section
+
+	err := fsext.Walk(afs, dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := fsext.ReadFile(afs, path)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		fm, err := ParseFrontmatter(string(content))
+		if err != nil {
+			warn(fmt.Errorf("%s: %w", path, err))
+			return nil
+		}
+
+		slug := category
+		if s := SlugFromRelPath(rel); s != "." {
+			slug = category + "/" + s
+		}
+		title := fm.Title
+		if title == "" {
+			title = strings.TrimSuffix(info.Name(), ".md")
+		}
+
+		sections = append(sections, Section{
+			Slug:        slug,
+			RelPath:     filepath.ToSlash(rel),
+			Title:       title,
+			Description: fm.Description,
+			Weight:      fm.Weight,
+			Category:    category,
+			IsIndex:     info.Name() == "_index.md",
+			Markers:     DetectMarkers(slug, string(content), fm),
+			dir:         dir,
+		})
+		return nil
+	})
+
+	return sections, err
+}
+
+// mergeOverlay adds the sections of an overlay to idx. Sections whose slug
+// already exists are skipped and returned, so that upstream docs are never
+// shadowed. The _index.md of an overlay merged under an upstream category is
+// dropped without being returned, as the upstream page describes the
+// category. When the category is new and the overlay has no _index.md, a
+// category section is created for it.
+func (idx *Index) mergeOverlay(sections []Section, category, title string) []string {
+	parent, hasParent := idx.Lookup(category)
+
+	var skipped []string
+	added := make([]Section, 0, len(sections)+1)
+	for _, sec := range sections {
+		if _, ok := idx.Lookup(sec.Slug); ok {
+			if sec.Slug != category {
+				skipped = append(skipped, sec.Slug)
+			}
+			continue
+		}
+		added = append(added, sec)
+	}
+
+	if !hasParent {
+		if idx.overlays == nil {
+			idx.overlays = make(map[string]bool)
+		}
+		idx.overlays[strings.ToLower(category)] = true
+	}
+	if !hasParent && !containsSlug(added, category) {
+		if title == "" {
+			title = strings.ToUpper(category[:1]) + category[1:]
+		}
+		added = append(added, Section{
+			Slug:     category,
+			Title:    title,
+			Weight:   overlayWeight,
+			Category: category,
+			IsIndex:  true,
+		})
+	}
+	// Category must be an index for PopulateChildren to attach the
+	// overlay's top-level pages to it.
+	for i := range added {
+		if added[i].Slug == category {
+			added[i].IsIndex = true
+		}
+	}
+	PopulateChildren(added)
+
+	if hasParent {
+		for _, sec := range added {
+			if !strings.Contains(strings.TrimPrefix(sec.Slug, category+"/"), "/") {
+				parent.Children = append(parent.Children, sec.Slug)
+			}
+		}
+	}
+
+	idx.Sections = append(idx.Sections, added...)
+	idx.reindex()
+
+	return skipped
+}
+
+// isOverlay reports whether sec comes from an overlay rather than the docs
+// bundle, including the category section created for an overlay.
+func (idx *Index) isOverlay(sec *Section) bool {
+	return sec.dir != "" || idx.overlays[strings.ToLower(sec.Slug)]
+}
+
+// containsSlug reports whether sections has a section with slug.
+func containsSlug(sections []Section, slug string) bool {
+	for _, sec := range sections {
+		if sec.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeOverlays merges every configured overlay into idx. Problems with one
+// overlay are reported through warn and do not stop the others from loading.
+func mergeOverlays(afs fsext.Fs, env map[string]string, idx *Index, overlays []overlayConfig, warn func(error)) {
+	for _, ov := range overlays {
+		category := ov.Category
+		if category == "" {
+			category = defaultOverlayCategory
+		}
+		if strings.ContainsAny(category, `/\ `) {
+			warn(fmt.Errorf("overlay %s: category %q must be a single path segment", ov.Dir, category))
+			continue
+		}
+
+		dir, err := overlayDir(env, ov.Dir)
+		if err == nil && ov.Dir == "" {
+			err = errors.New("dir is required")
+		}
+		if err != nil {
+			warn(fmt.Errorf("overlay %s: %w", ov.Dir, err))
+			continue
+		}
+
+		sections, err := loadOverlay(afs, dir, category, func(err error) {
+			warn(fmt.Errorf("overlay %s: skipping %w", ov.Dir, err))
+		})
+		if err != nil {
+			warn(fmt.Errorf("overlay %s: %w", ov.Dir, err))
+			continue
+		}
+		for _, slug := range idx.mergeOverlay(sections, category, ov.Title) {
+			warn(fmt.Errorf("overlay %s: %s already exists, keeping the upstream page", ov.Dir, slug))
+		}
+	}
+}
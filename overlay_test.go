@@ -0,0 +1,173 @@
+package docs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// setupOverlay writes overlay docs and a docs.yaml that merges them, and
+// returns a runner for the docs command.
+func setupOverlay(t *testing.T) func(t *testing.T, args ...string) string {
+	t.Helper()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["HOME"] = "/home/test"
+	gs.Env["XDG_CONFIG_HOME"] = "/home/test/.config"
+
+	files := map[string]string{
+		"/home/test/.config/k6/docs.yaml": "overlays:\n" +
+			"  - dir: ~/company/k6-docs\n    title: Company libraries\n" +
+			"  - dir: team-examples\n    category: examples\n",
+		"/home/test/company/k6-docs/auth-helpers.md": "---\ntitle: Auth helpers\n" +
+			"description: Log in to internal services.\nweight: 2\n---\n\n## Auth helpers\n\n" +
+			"Use `login()` from the shared library.\n",
+		"/home/test/company/k6-docs/retry/_index.md": "---\ntitle: Retry\n" +
+			"description: Retry flaky requests.\nweight: 1\n---\n\n## Retry\n\nBack off between attempts.\n",
+		"/home/test/company/k6-docs/retry/backoff.md": "---\ntitle: backoff\n---\n\nExponential backoff.\n",
+		"/home/test/.config/k6/team-examples/login-flow.md": "---\ntitle: Login flow\n" +
+			"description: Script a login flow.\n---\n\nA team example.\n",
+		"/home/test/.config/k6/team-examples/websockets.md": "---\ntitle: Shadow\n---\n\nShould not replace upstream.\n",
+		"/home/test/company/k6-docs/broken.md":              "---\ntitle: 'Broken\n---\n\nUnterminated quote.\n",
+	}
+	for path, content := range files {
+		if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsext.WriteFile(afs, path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return func(t *testing.T, args ...string) string {
+		t.Helper()
+		cmd := newCmd(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute(%v): %v", args, err)
+		}
+		return buf.String()
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	t.Parallel()
+
+	run := setupOverlay(t)
+
+	t.Run("toc", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "overlay/toc.txt", run(t))
+	})
+
+	t.Run("view", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "internal", "auth-helpers")
+		if !strings.Contains(out, "Use `login()` from the shared library.") {
+			t.Errorf("expected overlay content, got:\n%s", out)
+		}
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "internal", "retry")
+		if !strings.Contains(out, "Back off between attempts.") || !strings.Contains(out, "backoff") {
+			t.Errorf("expected overlay index with subtopics, got:\n%s", out)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "search", "exponential")
+		if !strings.Contains(out, "internal:") || !strings.Contains(out, "retry/backoff") {
+			t.Errorf("expected overlay search result, got:\n%s", out)
+		}
+	})
+
+	t.Run("invalid_frontmatter", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "internal", "--list")
+		if !strings.Contains(out, "auth-helpers") || strings.Contains(out, "broken") {
+			t.Errorf("expected the broken file skipped and the others listed, got:\n%s", out)
+		}
+	})
+
+	t.Run("llms", func(t *testing.T) {
+		t.Parallel()
+		for _, args := range [][]string{{"export", "llms"}, {"export", "llms", "--full"}} {
+			out := run(t, args...)
+			if !strings.Contains(out, "k6 x docs internal auth-helpers") {
+				t.Errorf("%v: expected the overlay command, got:\n%s", args, out)
+			}
+			if strings.Contains(out, "/docs/k6/v0.55.x/internal") {
+				t.Errorf("%v: overlay sections have no grafana.com URL:\n%s", args, out)
+			}
+		}
+	})
+
+	t.Run("upstream_category", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "examples", "--list")
+		if !strings.Contains(out, "login-flow") || !strings.Contains(out, "websockets") {
+			t.Errorf("expected team example next to upstream examples, got:\n%s", out)
+		}
+		if out := run(t, "examples", "websockets"); strings.Contains(out, "Should not replace upstream.") {
+			t.Errorf("overlay page shadowed an upstream page:\n%s", out)
+		}
+	})
+}
+
+func TestMergeOverlay_UpstreamCategoryIndex(t *testing.T) {
+	t.Parallel()
+
+	idx := &Index{Sections: []Section{{Slug: "examples", Category: "examples", IsIndex: true}}}
+	idx.reindex()
+
+	skipped := idx.mergeOverlay([]Section{
+		{Slug: "examples", RelPath: "_index.md", Category: "examples", IsIndex: true},
+		{Slug: "examples/login-flow", RelPath: "login-flow.md", Category: "examples"},
+	}, "examples", "")
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want the category _index.md dropped without a warning", skipped)
+	}
+	if _, ok := idx.Lookup("examples/login-flow"); !ok {
+		t.Error("expected examples/login-flow to be merged")
+	}
+	// Upstream categories resolve as before; only new categories are overlays.
+	if idx.overlays["examples"] {
+		t.Error("examples should not be recorded as an overlay category")
+	}
+}
+
+func TestOverlay_NoDocsURL(t *testing.T) {
+	t.Parallel()
+
+	idx := &Index{Sections: []Section{
+		{Slug: "using-k6", Title: "Using k6", Category: "using-k6", IsIndex: true},
+	}}
+	idx.reindex()
+	idx.mergeOverlay([]Section{
+		{Slug: "internal/auth", RelPath: "auth.md", Title: "Auth", Category: "internal", dir: "/overlay"},
+	}, "internal", "")
+
+	for _, slug := range []string{"internal", "internal/auth"} {
+		sec, _ := idx.Lookup(slug)
+		if out := manPage(idx, sec, "Text.\n", "v1.0.0"); strings.Contains(out, "grafana.com") {
+			t.Errorf("man page of %s links to grafana.com:\n%s", slug, out)
+		}
+		if out := htmlPage(idx, sec, "Text.\n", "v1.0.0"); strings.Contains(out, "<footer>") {
+			t.Errorf("HTML page of %s has a source footer:\n%s", slug, out)
+		}
+	}
+	sec, _ := idx.Lookup("using-k6")
+	if out := htmlPage(idx, sec, "Text.\n", "v1.0.0"); !strings.Contains(out, "https://grafana.com/docs/k6/v1.0.0/using-k6/") {
+		t.Errorf("expected a source footer for upstream pages:\n%s", out)
+	}
+}
@@ -0,0 +1,72 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.k6.io/k6/cmd/state"
+	"golang.org/x/term"
+)
+
+// defaultPager is used when neither the config nor $PAGER names a pager.
+// -R keeps the ANSI styling of the built-in renderer.
+const defaultPager = "less -R"
+
+// pagerCommand returns the pager to use: the pager config key, then $PAGER,
+// then the default. "none" in the config disables paging.
+func pagerCommand(cfg docsConfig, env map[string]string) string {
+	switch {
+	case cfg.Pager == rendererNone:
+		return ""
+	case cfg.Pager != "":
+		return cfg.Pager
+	case strings.TrimSpace(env["PAGER"]) != "":
+		return env["PAGER"]
+	default:
+		return defaultPager
+	}
+}
+
+// terminalHeight returns the height of the terminal attached to stdout.
+// $LINES takes precedence. It returns 0 when the height is unknown.
+func terminalHeight(gs *state.GlobalState) int {
+	if lines, err := strconv.Atoi(gs.Env["LINES"]); err == nil && lines > 0 {
+		return lines
+	}
+	if _, h, err := term.GetSize(gs.Stdout.RawOutFd); err == nil && h > 0 {
+		return h
+	}
+	return 0
+}
+
+// page writes content to direct when it fits in height lines, and otherwise
+// pipes it through pager, which writes to the terminal at pagerOut. If the
+// pager is empty, the height is unknown, or the pager cannot be started,
+// content is written to direct.
+func page(
+	ctx context.Context, content []byte, direct, pagerOut, stderr io.Writer, pager string, height int,
+) error {
+	parts := strings.Fields(pager)
+	if len(parts) == 0 || height <= 0 || bytes.Count(content, []byte("\n")) < height {
+		_, err := direct.Write(content)
+		return err
+	}
+
+	pc := exec.CommandContext(ctx, parts[0], parts[1:]...) //nolint:gosec // user-configured pager
+	pc.Stdin = bytes.NewReader(content)
+	pc.Stdout = pagerOut
+	pc.Stderr = stderr
+
+	if err := pc.Start(); err != nil {
+		_, writeErr := direct.Write(content)
+		return writeErr
+	}
+
+	// The pager's exit status only reflects how the user left it.
+	_ = pc.Wait()
+	return nil
+}
@@ -0,0 +1,134 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestPagerCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  docsConfig
+		env  map[string]string
+		want string
+	}{
+		{name: "default", env: map[string]string{}, want: "less -R"},
+		{name: "env", env: map[string]string{"PAGER": "more"}, want: "more"},
+		{name: "config_wins", cfg: docsConfig{Pager: "most"}, env: map[string]string{"PAGER": "more"}, want: "most"},
+		{name: "config_none", cfg: docsConfig{Pager: "none"}, env: map[string]string{"PAGER": "more"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := pagerCommand(tt.cfg, tt.env); got != tt.want {
+				t.Errorf("pagerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("one\ntwo\nthree\n")
+	pager := "sed s/^/P:/"
+
+	tests := []struct {
+		name       string
+		pager      string
+		height     int
+		wantDirect string
+		wantPaged  string
+	}{
+		{name: "fits", pager: pager, height: 10, wantDirect: string(content)},
+		{name: "too_tall", pager: pager, height: 2, wantPaged: "P:one\nP:two\nP:three\n"},
+		{name: "unknown_height", pager: pager, height: 0, wantDirect: string(content)},
+		{name: "no_pager", height: 2, wantDirect: string(content)},
+		{name: "missing_pager", pager: "no-such-pager-xyz", height: 2, wantDirect: string(content)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var direct, paged bytes.Buffer
+			err := page(context.Background(), content, &direct, &paged, io.Discard, tt.pager, tt.height)
+			if err != nil {
+				t.Fatalf("page: %v", err)
+			}
+			if direct.String() != tt.wantDirect {
+				t.Errorf("direct = %q, want %q", direct.String(), tt.wantDirect)
+			}
+			if paged.String() != tt.wantPaged {
+				t.Errorf("paged = %q, want %q", paged.String(), tt.wantPaged)
+			}
+		})
+	}
+}
+
+func TestPagerOnTTY(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, lines string, extra ...string) string {
+		t.Helper()
+		afs, cacheDir := setupTestCache(t)
+		gs := newTestGlobalState(t, afs)
+		gs.Env["XDG_CONFIG_HOME"] = "/tmp/pager-config"
+		gs.Env["LINES"] = lines
+		gs.Stdout.IsTTY = true
+
+		k6Dir := filepath.Join(gs.Env["XDG_CONFIG_HOME"], "k6")
+		if err := afs.MkdirAll(k6Dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		cfg := []byte("renderer: none\npager: sed s/^/P:/\n")
+		if err := fsext.WriteFile(afs, filepath.Join(k6Dir, "docs.yaml"), cfg, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		gs.Stdout.Writer = &buf
+
+		cmd := newCmd(gs)
+		cmd.SetOut(&buf)
+		cmd.SetErr(io.Discard)
+		args := append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x"}, extra...)
+		cmd.SetArgs(append(args, "http", "get"))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+		return buf.String()
+	}
+
+	t.Run("long_output_is_paged", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "3")
+		if !strings.HasPrefix(out, "P:## http.get(url)") {
+			t.Errorf("expected paged output, got: %q", out)
+		}
+	})
+
+	t.Run("short_output_is_not_paged", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "200")
+		if !strings.HasPrefix(out, "## http.get(url)") {
+			t.Errorf("expected unpaged output, got: %q", out)
+		}
+	})
+
+	t.Run("no_pager_flag", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "3", "--no-pager")
+		if !strings.HasPrefix(out, "## http.get(url)") {
+			t.Errorf("expected unpaged output, got: %q", out)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+)
+
+// TransformStage is a named step of the transform pipeline that cleans up
+// doc markdown at runtime.
+type TransformStage interface {
+	// Name identifies the stage in docs.yaml and flags, e.g. "links".
+	Name() string
+	// Description is a one-line summary shown by k6 x docs transforms.
+	Description() string
+	// Apply transforms markdown content for the given docs version.
+	Apply(content, version string) string
+}
+
+// StageScope selects the parts of a document a transform stage rewrites.
+type StageScope int
+
+const (
+	// ScopeProse stages rewrite prose only: fenced code blocks and inline
+	// code are masked while they run. Stages that do not implement
+	// ScopedStage have this scope.
+	ScopeProse StageScope = iota
+	// ScopeAll stages rewrite prose and, separately, every code segment.
+	ScopeAll
+	// ScopeDocument stages rewrite the raw document, code included, e.g. to
+	// restructure code blocks.
+	ScopeDocument
+)
+
+// ScopedStage is a TransformStage that declares the parts of a document it
+// rewrites.
+type ScopedStage interface {
+	TransformStage
+	Scope() StageScope
+}
+
+// funcStage is a TransformStage backed by a function.
+type funcStage struct {
+	name, description string
+	scope             StageScope
+	apply             func(content, version string) string
+}
+
+func (s funcStage) Name() string                         { return s.name }
+func (s funcStage) Description() string                  { return s.description }
+func (s funcStage) Scope() StageScope                    { return s.scope }
+func (s funcStage) Apply(content, version string) string { return s.apply(content, version) }
+
+// NewTransformStage returns a stage that applies fn to prose.
+func NewTransformStage(name, description string, fn func(content, version string) string) TransformStage {
+	return funcStage{name: name, description: description, apply: fn}
+}
+
+// NewScopedTransformStage returns a stage that applies fn to the parts of a
+// document selected by scope.
+func NewScopedTransformStage(
+	name, description string, scope StageScope, fn func(content, version string) string,
+) TransformStage {
+	return funcStage{name: name, description: description, scope: scope, apply: fn}
+}
+
+// stageScope returns the scope of s.
+func stageScope(s TransformStage) StageScope {
+	if ss, ok := s.(ScopedStage); ok {
+		return ss.Scope()
+	}
+	return ScopeProse
+}
+
+// Pipeline is an ordered list of transform stages, each of which can be
+// disabled. The zero value is an empty pipeline.
+type Pipeline struct {
+	stages   []TransformStage
+	disabled map[string]bool
+}
+
+// NewPipeline returns a pipeline of stages, all enabled.
+func NewPipeline(stages ...TransformStage) *Pipeline {
+	return &Pipeline{stages: stages, disabled: make(map[string]bool)}
+}
+
+// clone returns a copy of the pipeline that can be changed independently.
+func (p *Pipeline) clone() *Pipeline {
+	c := NewPipeline(p.Stages()...)
+	maps.Copy(c.disabled, p.disabled)
+	return c
+}
+
+// Stages returns the stages of the pipeline in order.
+func (p *Pipeline) Stages() []TransformStage {
+	return slices.Clone(p.stages)
+}
+
+// index returns the position of the stage named name, or -1.
+func (p *Pipeline) index(name string) int {
+	return slices.IndexFunc(p.stages, func(s TransformStage) bool { return s.Name() == name })
+}
+
+// Insert adds stage before the stage named before, or at the end when
+// before is empty. Stage names must be unique.
+func (p *Pipeline) Insert(stage TransformStage, before string) error {
+	if p.index(stage.Name()) >= 0 {
+		return fmt.Errorf("transform stage %q already exists", stage.Name())
+	}
+	if before == "" {
+		p.stages = append(p.stages, stage)
+		return nil
+	}
+	i := p.index(before)
+	if i < 0 {
+		return unknownStageError(before)
+	}
+	p.stages = slices.Insert(p.stages, i, stage)
+	return nil
+}
+
+// Enable turns on the stage named name.
+func (p *Pipeline) Enable(name string) error {
+	if p.index(name) < 0 {
+		return unknownStageError(name)
+	}
+	delete(p.disabled, name)
+	return nil
+}
+
+// Disable turns off the stage named name, so that Apply skips it.
+func (p *Pipeline) Disable(name string) error {
+	if p.index(name) < 0 {
+		return unknownStageError(name)
+	}
+	if p.disabled == nil {
+		p.disabled = make(map[string]bool)
+	}
+	p.disabled[name] = true
+	return nil
+}
+
+// Enabled reports whether the stage named name runs.
+func (p *Pipeline) Enabled(name string) bool {
+	return p.index(name) >= 0 && !p.disabled[name]
+}
+
+// Apply runs the enabled stages over content in order. Consecutive
+// built-in stages run together, in a single pass over the document. Other
+// stages see the parts of the document selected by their scope: code is
+// masked while they run.
+func (p *Pipeline) Apply(content, version string) string {
+	if content == "" {
+		return ""
+	}
+
+	var run builtin
+	for _, s := range p.stages {
+		if p.disabled[s.Name()] {
+			continue
+		}
+		if b, ok := s.(builtinStage); ok {
+			run |= b.stage
+			continue
+		}
+		if run != 0 {
+			content, run = transform(content, version, run), 0
+		}
+		content = applyScoped(s, content, version)
+	}
+	if run != 0 {
+		content = transform(content, version, run)
+	}
+	return content
+}
+
+// applyScoped runs s over the parts of content selected by its scope.
+func applyScoped(s TransformStage, content, version string) string {
+	scope := stageScope(s)
+	if scope == ScopeDocument {
+		return s.Apply(content, version)
+	}
+	prose, code := maskCode(content)
+	if scope == ScopeAll {
+		for i := range code {
+			code[i] = s.Apply(code[i], version)
+		}
+	}
+	return unmaskCode(s.Apply(prose, version), code)
+}
+
+func unknownStageError(name string) error {
+	return fmt.Errorf("unknown transform stage %q (list them with: k6 x docs transforms)", name)
+}
+
+// transformsConfig enables and disables transform stages by name.
+type transformsConfig struct {
+	Enable  []string `yaml:"enable"`
+	Disable []string `yaml:"disable"`
+}
+
+// transformPipeline returns the pipeline for a command: the default stages,
+// adjusted by opts.customize, then the config, then the flags.
+func transformPipeline(cfg docsConfig, opts *docsOpts) (*Pipeline, error) {
+	p := DefaultPipeline()
+	if opts.customize != nil {
+		if err := opts.customize(p); err != nil {
+			return nil, fmt.Errorf("customize transforms: %w", err)
+		}
+	}
+
+	for _, step := range []struct {
+		names []string
+		apply func(string) error
+	}{
+		{cfg.Transforms.Enable, p.Enable},
+		{cfg.Transforms.Disable, p.Disable},
+		{opts.enableTransforms, p.Enable},
+		{opts.disableTransforms, p.Disable},
+	} {
+		for _, name := range step.names {
+			if err := step.apply(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+func newTransformsCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	return &cobra.Command{
+		Use:   "transforms",
+		Short: "List the transform stages applied to doc content",
+		Long: "List the stages that clean up doc markdown, in the order they run. Disable a stage with\n" +
+			"--disable-transform <name> or transforms.disable in docs.yaml.",
+		Example: "  k6 x docs transforms\n  k6 x docs http get --disable-transform links",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, cfgErr := loadConfig(gs.FS, gs.Env)
+			if cfgErr != nil {
+				gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
+			}
+			p, err := transformPipeline(cfg, opts)
+			if err != nil {
+				return err
+			}
+			printTransforms(cmd.OutOrStdout(), p)
+			return nil
+		},
+	}
+}
+
+// printTransforms lists the stages of p in order, marking disabled ones.
+func printTransforms(w io.Writer, p *Pipeline) {
+	_, _ = fmt.Fprintln(w, "Transform stages, in order")
+	_, _ = fmt.Fprintln(w, "Use: k6 x docs <topic> --disable-transform <name>")
+	_, _ = fmt.Fprintln(w)
+
+	items := make([]listItem, 0, len(p.stages))
+	for _, s := range p.stages {
+		desc := s.Description()
+		if !p.Enabled(s.Name()) {
+			desc = "(disabled) " + desc
+		}
+		items = append(items, listItem{Name: s.Name(), Description: desc})
+	}
+	printAlignedList(w, items)
+}
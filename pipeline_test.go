@@ -0,0 +1,195 @@
+package docs
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	content := "---\ntitle: x\n---\nSee [the RFC](https://example.com/rfc) ![diagram](d.png)<!-- todo -->\n"
+
+	footnote := "\n\n[^1]: https://example.com/rfc\n"
+
+	tests := []struct {
+		name    string
+		enable  []string
+		disable []string
+		want    string
+	}{
+		{name: "default", want: "See the RFC[^1] diagram" + footnote},
+		{name: "keep_links", disable: []string{"links"}, want: "See [the RFC](https://example.com/rfc) diagram\n"},
+		{name: "keep_images", disable: []string{"images"}, want: "See the RFC[^1] ![diagram](d.png)" + footnote},
+		{name: "keep_comments", disable: []string{"comments"}, want: "See the RFC[^1] diagram<!-- todo -->" + footnote},
+		{name: "inline_links", enable: []string{"inline-links"}, want: "See the RFC (https://example.com/rfc) diagram\n"},
+		{
+			name:    "keep_frontmatter",
+			disable: []string{"frontmatter", "links"},
+			want:    "---\ntitle: x\n---\nSee [the RFC](https://example.com/rfc) diagram\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := DefaultPipeline()
+			for _, name := range tt.enable {
+				if err := p.Enable(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, name := range tt.disable {
+				if err := p.Disable(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := p.Apply(content, "v1.0.0"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_StageApply(t *testing.T) {
+	t.Parallel()
+
+	content := "<br>[a](https://example.com/<K6_VERSION>/a) `<K6_VERSION> [b](c)`\n\n\n"
+	want := map[string]string{
+		"line-breaks": "[a](https://example.com/<K6_VERSION>/a) `<K6_VERSION> [b](c)`\n\n\n",
+		"version":     "<br>[a](https://example.com/v1.0.0/a) `v1.0.0 [b](c)`\n\n\n",
+		"links":       "<br>a[^1] `<K6_VERSION> [b](c)`\n\n[^1]: https://example.com/<K6_VERSION>/a\n",
+		"whitespace":  "<br>[a](https://example.com/<K6_VERSION>/a) `<K6_VERSION> [b](c)`\n\n",
+	}
+	for _, s := range DefaultPipeline().Stages() {
+		if w, ok := want[s.Name()]; ok {
+			if got := s.Apply(content, "v1.0.0"); got != w {
+				t.Errorf("%s: got %q, want %q", s.Name(), got, w)
+			}
+		}
+	}
+}
+
+func TestPipelineInsert(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultPipeline()
+	latest := NewTransformStage("latest", "Link to the latest docs", func(s, _ string) string {
+		return strings.ReplaceAll(s, "<K6_VERSION>", "latest")
+	})
+
+	if err := p.Insert(latest, "version"); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Apply("Version <K6_VERSION>", "v1.0.0"); got != "Version latest" {
+		t.Errorf("stage before version: got %q", got)
+	}
+
+	if err := p.Insert(latest, ""); err == nil {
+		t.Error("expected an error for a duplicate stage")
+	}
+	if err := p.Insert(NewTransformStage("x", "", nil), "nope"); err == nil {
+		t.Error("expected an error for an unknown stage")
+	}
+	if err := p.Disable("nope"); err == nil || !strings.Contains(err.Error(), `unknown transform stage "nope"`) {
+		t.Errorf("Disable(nope) = %v", err)
+	}
+
+	names := make([]string, 0, len(p.Stages()))
+	for _, s := range p.Stages() {
+		names = append(names, s.Name())
+	}
+	if got := strings.Join(names, " "); !strings.Contains(got, "line-breaks latest version") {
+		t.Errorf("stages = %s", got)
+	}
+}
+
+func TestTransformFlags(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+	gs.Env["XDG_CONFIG_HOME"] = "/home/test/.config"
+
+	page := "## http.get(url)\n\nSee [the RFC](https://example.com/rfc).\n"
+	files := map[string]string{
+		filepath.Join(cacheDir, "markdown", "javascript-api", "k6-http", "get.md"): page,
+		"/home/test/.config/k6/docs.yaml":                                          "transforms:\n  disable: [links]\n",
+	}
+	for path, content := range files {
+		if err := afs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsext.WriteFile(afs, path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(t *testing.T, customize func(*Pipeline) error, args ...string) (string, error) {
+		t.Helper()
+		cmd := NewCommand(customize)(gs)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x"}, args...))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, nil, "http", "get")
+		if err != nil || !strings.Contains(out, "[the RFC](https://example.com/rfc)") {
+			t.Errorf("expected the link to be kept, got %v:\n%s", err, out)
+		}
+	})
+
+	t.Run("flag_overrides_config", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, nil, "http", "get", "--enable-transform", "links")
+		if err != nil || !strings.Contains(out, "See the RFC[^1].") {
+			t.Errorf("expected the link to be stripped, got %v:\n%s", err, out)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		_, err := run(t, nil, "http", "get", "--disable-transform", "nope")
+		if err == nil || !strings.Contains(err.Error(), `unknown transform stage "nope"`) {
+			t.Errorf("expected unknown stage error, got %v", err)
+		}
+	})
+
+	t.Run("custom_stage", func(t *testing.T) {
+		t.Parallel()
+		redact := func(p *Pipeline) error {
+			return p.Insert(NewTransformStage("redact", "Redact example.com", func(s, _ string) string {
+				return strings.ReplaceAll(s, "example.com", "[redacted]")
+			}), "")
+		}
+		out, err := run(t, redact, "http", "get")
+		if err != nil || !strings.Contains(out, "https://[redacted]/rfc") {
+			t.Errorf("expected the custom stage to run, got %v:\n%s", err, out)
+		}
+
+		_, err = run(t, func(*Pipeline) error { return errors.New("boom") }, "http", "get")
+		if err == nil || !strings.Contains(err.Error(), "customize transforms: boom") {
+			t.Errorf("expected customize error, got %v", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, nil, "transforms", "--disable-transform", "comments")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "transforms/list.txt", out)
+	})
+}
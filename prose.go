@@ -0,0 +1,170 @@
+package docs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// codeMark delimits the placeholders that stand in for code while prose is
+// transformed. NUL does not occur in markdown and matches none of the
+// transform patterns.
+const codeMark = "\x00"
+
+// maskCode replaces the bodies of fenced code blocks and inline code spans
+// in s with placeholders, so that prose transforms cannot rewrite code. The
+// fence lines stay in the prose, where stages can still see the language of
+// a block. It returns the masked text and the code segments, which
+// unmaskCode puts back. A fence without a closing fence runs to the end of
+// s, and a backtick run without a matching run is kept as text.
+func maskCode(s string) (string, []string) {
+	var (
+		sb   strings.Builder
+		segs []string
+		body []string
+		f    fence
+	)
+	placeholder := func(code string) string {
+		segs = append(segs, code)
+		return codeMark + strconv.Itoa(len(segs)-1) + codeMark
+	}
+	flush := func() {
+		if len(body) == 0 {
+			return
+		}
+		// The line break after the last line of code stays in the prose.
+		code, nl := strings.CutSuffix(strings.Join(body, ""), "\n")
+		sb.WriteString(placeholder(code))
+		if nl {
+			sb.WriteString("\n")
+		}
+		body = body[:0]
+	}
+
+	for line := range strings.SplitAfterSeq(s, "\n") {
+		inFence := f.inside()
+		switch {
+		case f.step(line):
+			flush()
+			sb.WriteString(line)
+		case inFence:
+			body = append(body, line)
+		default:
+			sb.WriteString(maskInlineCode(line, placeholder))
+		}
+	}
+	flush()
+	return sb.String(), segs
+}
+
+// maskInlineCode replaces the code spans of a line: a run of backticks up to
+// the next run of the same length.
+func maskInlineCode(line string, placeholder func(string) string) string {
+	if !strings.Contains(line, "`") {
+		return line
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			sb.WriteByte(line[i])
+			i++
+			continue
+		}
+		n := backtickRun(line, i)
+		end := -1
+		for j := i + n; j < len(line); {
+			if line[j] != '`' {
+				j++
+				continue
+			}
+			m := backtickRun(line, j)
+			if m == n {
+				end = j + m
+				break
+			}
+			j += m
+		}
+		if end < 0 {
+			sb.WriteString(line[i : i+n])
+			i += n
+			continue
+		}
+		sb.WriteString(placeholder(line[i:end]))
+		i = end
+	}
+	return sb.String()
+}
+
+// backtickRun returns the number of consecutive backticks at line[i:].
+func backtickRun(line string, i int) int {
+	n := 0
+	for i+n < len(line) && line[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// unmaskCode puts the code segments replaced by maskCode back into s. When
+// a stage has quoted the line of a placeholder, every line of the code gets
+// the same quote prefix.
+func unmaskCode(s string, segs []string) string {
+	if len(segs) == 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, codeMark)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start+1:], codeMark)
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		n, err := strconv.Atoi(s[start+1 : end])
+		if err != nil || n >= len(segs) {
+			sb.WriteString(s[:start+1])
+			s = s[start+1:]
+			continue
+		}
+		// A line that already holds earlier code is not only quote markers.
+		prefix := ""
+		if sb.Len() == 0 || strings.Contains(s[:start], "\n") {
+			prefix = quotePrefix(s[:start])
+		}
+		sb.WriteString(s[:start])
+		sb.WriteString(quoteLines(segs[n], prefix))
+		s = s[end+1:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// quotePrefix returns the blockquote markers ("> ") that start the last line
+// of s, if that line holds nothing else.
+func quotePrefix(s string) string {
+	line := s[strings.LastIndex(s, "\n")+1:]
+	if !strings.Contains(line, ">") || strings.Trim(line, "> ") != "" {
+		return ""
+	}
+	return line
+}
+
+// quoteLines puts prefix before every line of code but the first.
+func quoteLines(code, prefix string) string {
+	if prefix == "" || !strings.Contains(code, "\n") {
+		return code
+	}
+	blank := strings.TrimRight(prefix, " ")
+	lines := strings.Split(code, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "" {
+			lines[i] = blank
+		} else {
+			lines[i] = prefix + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
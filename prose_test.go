@@ -0,0 +1,143 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		code  []string
+	}{
+		{
+			name:  "no code",
+			input: "Plain [text](url).\n",
+			want:  "Plain [text](url).\n",
+		},
+		{
+			name:  "fenced",
+			input: "Before\n\n```js\nlet a = [1](2);\n```\nAfter\n",
+			want:  "Before\n\n```js\n\x000\x00\n```\nAfter\n",
+			code:  []string{"let a = [1](2);"},
+		},
+		{
+			name:  "inline",
+			input: "Call `get([url])` or ``a ` b``.\n",
+			want:  "Call \x000\x00 or \x001\x00.\n",
+			code:  []string{"`get([url])`", "``a ` b``"},
+		},
+		{
+			name:  "empty fence",
+			input: "```\n```\n",
+			want:  "```\n```\n",
+		},
+		{
+			name:  "unmatched backtick",
+			input: "A ` alone\n",
+			want:  "A ` alone\n",
+		},
+		{
+			name:  "nested fence",
+			input: "````markdown\n```js\nlet a = [1](2);\n```\n````\n[text](url)\n",
+			want:  "````markdown\n\x000\x00\n````\n[text](url)\n",
+			code:  []string{"```js\nlet a = [1](2);\n```"},
+		},
+		{
+			name:  "tilde fence with backticks",
+			input: "~~~\n```\n~~~\n",
+			want:  "~~~\n\x000\x00\n~~~\n",
+			code:  []string{"```"},
+		},
+		{
+			name:  "unterminated fence",
+			input: "Text\n```\ncode <br>\n",
+			want:  "Text\n```\n\x000\x00\n",
+			code:  []string{"code <br>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, code := maskCode(tt.input)
+			if got != tt.want {
+				t.Errorf("masked = %q, want %q", got, tt.want)
+			}
+			if strings.Join(code, "|") != strings.Join(tt.code, "|") {
+				t.Errorf("code = %q, want %q", code, tt.code)
+			}
+			if back := unmaskCode(got, code); back != tt.input {
+				t.Errorf("unmasked = %q, want %q", back, tt.input)
+			}
+		})
+	}
+}
+
+func TestTransform_LeavesCodeAlone(t *testing.T) {
+	t.Parallel()
+
+	code := "```javascript\n" +
+		"// See [the docs](https://example.com) <br>\n" +
+		"const tpl = `{{< name >}}`; <!-- not a comment -->\n" +
+		"\n\n\n" +
+		"const url = 'https://grafana.com/docs/k6/<K6_VERSION>/';\n" +
+		"```\n"
+	input := "See [links](https://example.com) and `[a](b)`.<br/>\n\n" + code
+
+	want := "See links[^1] and `[a](b)`.\n\n" +
+		strings.ReplaceAll(code, "<K6_VERSION>", "v1.0.0") +
+		"\n[^1]: https://example.com\n"
+	if got := Transform(input, "v1.0.0"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnmaskCode_Quoted(t *testing.T) {
+	t.Parallel()
+
+	segs := []string{"if (a) {\n\n  b();\n}"}
+	got := unmaskCode("> ```js\n> \x000\x00\n> ```\n", segs)
+	want := "> ```js\n> if (a) {\n>\n>   b();\n> }\n> ```\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTransform_ShortcodesInCode(t *testing.T) {
+	t.Parallel()
+
+	code := "```markdown\n" +
+		"{{< code >}}\n" +
+		"{{< admonition type=\"note\" >}}\n" +
+		"Shortcodes are examples here.\n" +
+		"{{< /admonition >}}\n" +
+		"{{< /code >}}\n" +
+		"```\n"
+	input := "Write tabs with `{{< code >}}`:\n\n" + code
+	if got := Transform(input, "v1.0.0"); got != input {
+		t.Errorf("got:\n%s\nwant:\n%s", got, input)
+	}
+}
+
+func BenchmarkTransform(b *testing.B) {
+	section := "## Section\n\n" +
+		"Send a [request](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/get/) " +
+		"with `http.get(url)`. See the [guide](https://example.com/guide).<br/>\n\n" +
+		"{{< admonition type=\"note\" >}}\nChecks do not fail the test. <!-- TODO -->\n{{< /admonition >}}\n\n" +
+		"{{< code >}}\n\n```javascript\nimport http from 'k6/http';\n\nexport default function () {\n" +
+		"  http.get('https://quickpizza.grafana.com/'); // [not a link](x)\n}\n```\n\n" +
+		"```typescript\nimport http from 'k6/http';\n\nexport default function (): void {\n" +
+		"  http.get('https://quickpizza.grafana.com/');\n}\n```\n\n{{< /code >}}\n\n\n\n"
+	doc := "---\ntitle: 'get( url, [params] )'\n---\n\n" + strings.Repeat(section, 20)
+
+	b.SetBytes(int64(len(doc)))
+	for b.Loop() {
+		Transform(doc, "v1.0.0")
+	}
+}
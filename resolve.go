@@ -87,3 +87,28 @@ func withParentFallback(slug string, exists func(string) bool) string {
 	}
 	return slug
 }
+
+// resolve converts CLI args into a slug of idx. Args starting with the
+// category of an overlay are joined like those of an upstream category
+// (Rule 2 of [ResolveWithLookup]); other args resolve as usual.
+func (idx *Index) resolve(args []string) string {
+	if len(args) > 0 && idx.overlays[strings.ToLower(args[0])] {
+		return strings.Join(args, "/")
+	}
+	return ResolveWithLookup(args, func(s string) bool {
+		_, ok := idx.Lookup(s)
+		return ok
+	})
+}
+
+// commandArgs returns the CLI args that resolve back to slug, for printing
+// "k6 x docs ..." commands. The short form from [slugToArgs] is preferred;
+// the full slug is used when the short form resolves to a different section
+// (e.g. javascript-api/k6-jslib, whose short form "jslib" opens jslib).
+func commandArgs(idx *Index, slug string) string {
+	short := slugToArgs(slug)
+	if strings.EqualFold(idx.resolve(strings.Fields(short)), slug) {
+		return short
+	}
+	return slug
+}
@@ -39,3 +39,37 @@ func TestSlugResolution(t *testing.T) {
 		assertGolden(t, "view/jslib.txt", run(t, "jslib"))
 	})
 }
+
+func TestResolveWithLookup_Rule2(t *testing.T) {
+	t.Parallel()
+
+	// A top-level slug that is not a docs category resolves through the JS
+	// API shortcut, even when the lookup knows it.
+	exists := func(s string) bool {
+		return s == "http" || s == "javascript-api/k6-http/get"
+	}
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"using-k6", "scenarios"}, "using-k6/scenarios"},
+		{[]string{"http", "get"}, "javascript-api/k6-http/get"},
+		{[]string{"internal", "auth-helpers"}, "javascript-api/k6-internal/auth-helpers"},
+	}
+	for _, tt := range tests {
+		if got := ResolveWithLookup(tt.args, exists); got != tt.want {
+			t.Errorf("ResolveWithLookup(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	// Overlay categories are joined like upstream categories by the index.
+	idx := &Index{Sections: []Section{{Slug: "http", Category: "http"}}}
+	idx.reindex()
+	idx.mergeOverlay([]Section{{Slug: "internal/auth-helpers", Category: "internal"}}, "internal", "")
+	if got := idx.resolve([]string{"internal", "auth-helpers"}); got != "internal/auth-helpers" {
+		t.Errorf("resolve(internal auth-helpers) = %q, want internal/auth-helpers", got)
+	}
+	if got := idx.resolve([]string{"http"}); got != "javascript-api/k6-http" {
+		t.Errorf("resolve(http) = %q, want javascript-api/k6-http", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -20,6 +21,13 @@ type Section struct {
 	Category    string   `json:"category"`
 	Children    []string `json:"children"`
 	IsIndex     bool     `json:"is_index"`
+	Markers
+	// Signature is the call signature of a JavaScript API function page.
+	Signature *Signature `json:"signature,omitempty"`
+
+	// dir is the directory RelPath is relative to for overlay sections,
+	// which are not part of the bundle. It is empty for bundle sections.
+	dir string
 }
 
 // Index holds all sections and provides fast lookup by slug.
@@ -27,6 +35,13 @@ type Index struct {
 	Version  string    `json:"version"`
 	Sections []Section `json:"sections"`
 	bySlug   map[string]*Section
+	// pipeline transforms section content; nil uses the default pipeline.
+	pipeline *Pipeline
+	// filter selects the sections listed by the TOC, lists and search.
+	filter sectionFilter
+	// overlays holds the categories added by overlays, which resolve like
+	// upstream categories.
+	overlays map[string]bool
 }
 
 // LoadIndex reads sections.json from dir and returns a populated Index.
@@ -41,12 +56,25 @@ func LoadIndex(afs fsext.Fs, dir string) (*Index, error) {
 		return nil, fmt.Errorf("parse index %s: %w", dir, err)
 	}
 
+	idx.reindex()
+
+	return &idx, nil
+}
+
+// transform applies the transform pipeline of the index to content.
+func (idx *Index) transform(content, version string) string {
+	if idx == nil || idx.pipeline == nil {
+		return Transform(content, version)
+	}
+	return idx.pipeline.Apply(content, version)
+}
+
+// reindex rebuilds the slug lookup table after Sections changed.
+func (idx *Index) reindex() {
 	idx.bySlug = make(map[string]*Section, len(idx.Sections))
 	for i := range idx.Sections {
 		idx.bySlug[idx.Sections[i].Slug] = &idx.Sections[i]
 	}
-
-	return &idx, nil
 }
 
 // Lookup returns the section with the given slug in O(1) time.
@@ -111,6 +139,11 @@ func (idx *Index) Search(term string, readContent func(slug string) string) []*S
 	return results
 }
 
+// listed returns the sections of secs selected by the filter of the index.
+func (idx *Index) listed(secs []*Section) []*Section {
+	return slices.DeleteFunc(secs, func(sec *Section) bool { return !idx.filter.match(sec) })
+}
+
 // Children returns the child sections of the given slug, sorted by weight.
 // Returns nil if the slug is not found.
 func (idx *Index) Children(slug string) []*Section {
@@ -150,3 +183,26 @@ func (idx *Index) TopLevel() []*Section {
 
 	return top
 }
+
+// Walk visits every section reachable from the top-level categories in
+// depth-first order, children sorted by weight. depth is 0 for top-level
+// sections. Each section is visited at most once.
+func (idx *Index) Walk(fn func(sec *Section, depth int)) {
+	seen := make(map[string]bool, len(idx.Sections))
+
+	var visit func(sec *Section, depth int)
+	visit = func(sec *Section, depth int) {
+		if seen[sec.Slug] {
+			return
+		}
+		seen[sec.Slug] = true
+		fn(sec, depth)
+		for _, child := range idx.Children(sec.Slug) {
+			visit(child, depth+1)
+		}
+	}
+
+	for _, top := range idx.TopLevel() {
+		visit(top, 0)
+	}
+}
@@ -1,6 +1,8 @@
 package docs
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"go.k6.io/k6/lib/fsext"
@@ -281,6 +283,26 @@ func TestTopLevel(t *testing.T) {
 	}
 }
 
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	idx := mustLoadIndex(t)
+
+	var got []string
+	idx.Walk(func(sec *Section, depth int) {
+		got = append(got, fmt.Sprintf("%d:%s", depth, sec.Slug))
+	})
+
+	want := []string{
+		"0:getting-started", "1:installation", "1:first-test",
+		"0:results",
+		"0:protocols", "1:http", "1:grpc",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Walk order:\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
 func TestChildrenWithMissingChildSlug(t *testing.T) {
 	t.Parallel()
 
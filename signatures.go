@@ -0,0 +1,283 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+)
+
+var (
+	// reCallSpace matches the padding inside the parentheses of a call, as in
+	// "get( url, [params] )".
+	reCallSpace = regexp.MustCompile(`\(\s+|\s+\)`)
+	// reDefault matches a default value stated in a parameter description.
+	// Captures: [1]=value
+	reDefault = regexp.MustCompile("(?i)\\bdefault(?:s to|\\s+is|\\s+value is|:)\\s*`([^`]+)`")
+)
+
+// Param is a parameter of a JavaScript API function.
+type Param struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Signature is the call signature of a JavaScript API function, parsed from
+// its page by cmd/prepare.
+type Signature struct {
+	// Name is the call as written in the docs, e.g. "get(url, [params])".
+	Name    string  `json:"name"`
+	Params  []Param `json:"params,omitempty"`
+	Returns string  `json:"returns,omitempty"`
+	// ReturnsDescription describes the return value.
+	ReturnsDescription string `json:"returns_description,omitempty"`
+}
+
+// Line returns the signature on one line, e.g. "get(url, [params]) → Response".
+func (s *Signature) Line() string {
+	if s.Returns == "" {
+		return s.Name
+	}
+	return s.Name + " → " + s.Returns
+}
+
+// signatureLine returns the signature of sec on one line, or "".
+func signatureLine(sec *Section) string {
+	if sec.Signature == nil {
+		return ""
+	}
+	return sec.Signature.Line()
+}
+
+// ParseSignature extracts the signature of a function page from its title,
+// or its first heading if that is a call, and the tables of its content: the
+// first table with a parameter and a type column lists the parameters, and
+// the first table under a "Returns" heading gives the return type. It
+// returns nil for pages that do not document a call. Later headings are
+// not used: a class page documents its methods under headings such as
+// "Response.json( [selector] )".
+func ParseSignature(title, content string) *Signature {
+	sig := &Signature{Name: callName(title)}
+	heading, first := "", true
+	for _, b := range splitBlocks(content) {
+		switch b.kind {
+		case blockHeading:
+			_, heading = parseHeading(strings.TrimSpace(b.text))
+			if sig.Name == "" && first {
+				sig.Name = callName(heading)
+			}
+			first = false
+		case blockTable:
+			rows := tableRows(b.text)
+			if len(rows) < 2 {
+				continue
+			}
+			if strings.HasPrefix(strings.ToLower(heading), "return") {
+				if sig.Returns == "" {
+					sig.Returns, sig.ReturnsDescription = parseReturns(rows)
+				}
+			} else if sig.Params == nil {
+				sig.Params = parseParams(rows)
+			}
+		case blockProse, blockCode:
+		}
+	}
+
+	if sig.Name == "" {
+		return nil
+	}
+	return sig
+}
+
+// callName returns s with the padding inside its parentheses removed, or ""
+// if s is not a call.
+func callName(s string) string {
+	if !strings.Contains(s, "(") {
+		return ""
+	}
+	return reCallSpace.ReplaceAllStringFunc(strings.TrimSpace(s), strings.TrimSpace)
+}
+
+// tableRows returns the cells of a table with links replaced by their
+// text, without the delimiter row. The first row is the header.
+func tableRows(text string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if isDelimiterRow(cells) {
+			continue
+		}
+		for i, c := range cells {
+			cells[i] = rewriteLinks(c, func(text, _ string) string { return text })
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// column returns the index of the first header cell named one of names,
+// case-insensitively, or -1.
+func column(header []string, names ...string) int {
+	return slices.IndexFunc(header, func(c string) bool {
+		return slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(cell([]string{c}, 0), n) })
+	})
+}
+
+// cell returns row[i] as plain text, or "" if the row has no such cell.
+func cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(stripInline(row[i]))
+}
+
+// parseParams parses a parameter table, or returns nil if rows is another
+// kind of table.
+func parseParams(rows [][]string) []Param {
+	header := rows[0]
+	name := column(header, "parameter", "parameters", "param", "name", "argument")
+	typ := column(header, "type")
+	if name != 0 || typ < 0 {
+		return nil
+	}
+	def := column(header, "default", "default value")
+	desc := column(header, "description")
+
+	params := make([]Param, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		p := Param{Type: cell(row, typ), Default: cell(row, def), Description: cell(row, desc)}
+		p.Name, p.Optional = paramName(cell(row, name))
+		if p.Name == "" {
+			continue
+		}
+		if p.Default == "" && desc >= 0 && desc < len(row) {
+			// The description still has its inline code, which marks the value.
+			if m := reDefault.FindStringSubmatch(row[desc]); m != nil {
+				p.Default = m[1]
+			}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// paramName parses a parameter cell such as "params (optional)" or
+// "[params]" into the name and whether it is optional.
+func paramName(s string) (string, bool) {
+	optional := false
+	if before, _, ok := strings.Cut(s, "(optional)"); ok {
+		s, optional = before, true
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s, optional = s[1:len(s)-1], true
+	}
+	return strings.TrimSpace(s), optional
+}
+
+// parseReturns parses a table of return values into the type and the
+// description of the first one.
+func parseReturns(rows [][]string) (string, string) {
+	typ := column(rows[0], "type")
+	if typ < 0 {
+		typ = 0
+	}
+	return cell(rows[1], typ), cell(rows[1], column(rows[0], "description"))
+}
+
+func newSigCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	return &cobra.Command{
+		Use:   "sig <topic> [subtopic...]",
+		Short: "Print the signature of a JavaScript API function",
+		Long: "Print the parameters and return type of a JavaScript API function. For a module or class,\n" +
+			"print the signatures of its functions.",
+		Example: "  k6 x docs sig http get\n  k6 x docs sig http",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSig(gs, cmd, opts, args)
+		},
+	}
+}
+
+func runSig(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, args []string) error {
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+
+	_, _, idx, err := setup(gs, opts)
+	if err != nil {
+		return err
+	}
+	sec, err := resolveTopic(cmd.ErrOrStderr(), idx, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, cfgErr := loadConfig(gs.FS, gs.Env)
+	if cfgErr != nil {
+		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
+	}
+
+	w, render := newOutput(gs, cmd, cfg, opts)
+	if sec.Signature != nil {
+		printSignature(w, sec.Signature)
+		return render()
+	}
+
+	var items []listItem
+	for _, child := range idx.listed(idx.Children(sec.Slug)) {
+		if child.Signature != nil {
+			items = append(items, listItem{Name: childName(child.Slug, sec.Slug), Description: child.Signature.Line()})
+		}
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no signature for %s (read it with: k6 x docs %s)",
+			commandArgs(idx, sec.Slug), commandArgs(idx, sec.Slug))
+	}
+	_, _ = fmt.Fprintf(w, "%s\nUse: k6 x docs sig %s <function>\n\n", sec.Title, commandArgs(idx, sec.Slug))
+	printAlignedList(w, items)
+	return render()
+}
+
+// printSignature prints a signature with a line per parameter.
+func printSignature(w io.Writer, sig *Signature) {
+	_, _ = fmt.Fprintf(w, "`%s`\n", sig.Line())
+
+	if len(sig.Params) > 0 {
+		_, _ = fmt.Fprintln(w, "\nParameters:")
+		for _, p := range sig.Params {
+			var attrs []string
+			if p.Type != "" {
+				attrs = append(attrs, p.Type)
+			}
+			if p.Optional {
+				attrs = append(attrs, "optional")
+			}
+			if p.Default != "" {
+				attrs = append(attrs, "default "+p.Default)
+			}
+			line := "- " + p.Name
+			if len(attrs) > 0 {
+				line += " (" + strings.Join(attrs, ", ") + ")"
+			}
+			if p.Description != "" {
+				line += ": " + p.Description
+			}
+			_, _ = fmt.Fprintln(w, line)
+		}
+	}
+
+	if sig.Returns != "" {
+		line := "\nReturns: " + sig.Returns
+		if sig.ReturnsDescription != "" {
+			line += ": " + sig.ReturnsDescription
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
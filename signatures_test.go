@@ -0,0 +1,93 @@
+package docs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSignature(t *testing.T) {
+	t.Parallel()
+
+	content := "Make a GET request.\n\n" +
+		"| Parameter         | Type                                   | Description                                   |\n" +
+		"| ----------------- | -------------------------------------- | --------------------------------------------- |\n" +
+		"| url               | string / [HTTP URL](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/url) | " +
+		"Request URL (e.g. `http://example.com`). |\n" +
+		"| params (optional) | object                                 | [Params](https://example.com/params) object. " +
+		"Defaults to `{}`. |\n\n" +
+		"### Returns\n\n" +
+		"| Type     | Description            |\n" +
+		"| -------- | ---------------------- |\n" +
+		"| Response | HTTP Response object. |\n\n" +
+		"```javascript\n| not | a | table |\n```\n"
+
+	want := &Signature{
+		Name: "get(url, [params])",
+		Params: []Param{
+			{Name: "url", Type: "string / HTTP URL", Description: "Request URL (e.g. http://example.com)."},
+			{Name: "params", Type: "object", Optional: true, Default: "{}", Description: "Params object. Defaults to {}."},
+		},
+		Returns:            "Response",
+		ReturnsDescription: "HTTP Response object.",
+	}
+	if got := ParseSignature("get( url, [params] )", content); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := want.Line(); got != "get(url, [params]) → Response" {
+		t.Errorf("Line() = %q", got)
+	}
+
+	t.Run("default column", func(t *testing.T) {
+		t.Parallel()
+		got := ParseSignature("", "## sleep( t )\n\n| Parameter | Type | Default | Description |\n|-|-|-|-|\n"+
+			"| [t] | number | `1` | Duration in seconds. |\n")
+		want := &Signature{
+			Name:   "sleep(t)",
+			Params: []Param{{Name: "t", Type: "number", Optional: true, Default: "1", Description: "Duration in seconds."}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("not a call", func(t *testing.T) {
+		t.Parallel()
+		if got := ParseSignature("Response", "| Name | Type |\n|-|-|\n| body | string |\n"); got != nil {
+			t.Errorf("got %+v, want nil", got)
+		}
+	})
+
+	t.Run("class page", func(t *testing.T) {
+		t.Parallel()
+		content := "Response is the result of a request.\n\n## Properties\n\n" +
+			"| Name | Type | Description |\n|-|-|-|\n| body | string | Response body. |\n\n" +
+			"## Response.json( [selector] )\n\n" +
+			"| Parameter | Type | Description |\n|-|-|-|\n| selector (optional) | string | GJSON path. |\n"
+		if got := ParseSignature("Response", content); got != nil {
+			t.Errorf("got %+v, want nil", got)
+		}
+	})
+}
+
+func TestSigCommand(t *testing.T) {
+	t.Parallel()
+
+	run, runErr := setupCommand(t)
+
+	t.Run("function", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "sig/http-get.txt", run(t, "sig", "http", "get"))
+	})
+	t.Run("module", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "sig/http.txt", run(t, "sig", "http"))
+	})
+	t.Run("no_signature", func(t *testing.T) {
+		t.Parallel()
+		err := runErr(t, "sig", "using-k6", "scenarios")
+		if err == nil || !strings.Contains(err.Error(), "no signature for using-k6 scenarios") {
+			t.Errorf("expected no signature error, got %v", err)
+		}
+	})
+}
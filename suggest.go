@@ -0,0 +1,112 @@
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the number of candidates listed when a topic is not found.
+const maxSuggestions = 5
+
+// fuzzyMatch is a section scored by edit distance against a mistyped topic.
+type fuzzyMatch struct {
+	Section  *Section
+	Distance int
+}
+
+// displayArgs converts a slug to its shortest CLI form, stripping redundant
+// parent prefixes from each segment (e.g. cookiejar/cookiejar-clear → cookiejar clear).
+func displayArgs(slug string) string {
+	parts := strings.Split(slug, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		parts[i] = strings.TrimPrefix(parts[i], parts[i-1]+"-")
+	}
+	return slugToArgs(strings.Join(parts, "/"))
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	if a == b {
+		return 0
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// fuzzy returns sections whose CLI form or title is within a small edit
+// distance of args, closest first. The tolerance grows with the length of
+// the query so that short topics only forgive a single typo.
+func (idx *Index) fuzzy(args []string) []fuzzyMatch {
+	query := normalize(strings.Join(args, " "))
+	if query == "" {
+		return nil
+	}
+	maxDist := max(1, len(query)/4)
+
+	var matches []fuzzyMatch
+	for i := range idx.Sections {
+		sec := &idx.Sections[i]
+		best := -1
+		for _, form := range []string{slugToArgs(sec.Slug), displayArgs(sec.Slug), sec.Title} {
+			d := levenshtein(query, normalize(form))
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+		if best <= maxDist {
+			matches = append(matches, fuzzyMatch{Section: sec, Distance: best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Distance < matches[j].Distance
+	})
+
+	return matches
+}
+
+// resolveFuzzy is the fallback used when args do not resolve to a known slug.
+// A unique closest match is returned after printing a notice to notice.
+// Otherwise the returned error lists the closest candidates as commands.
+func resolveFuzzy(notice io.Writer, idx *Index, args []string) (*Section, error) {
+	topic := strings.Join(args, " ")
+	matches := idx.fuzzy(args)
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("topic not found: %s", topic)
+	}
+
+	if len(matches) == 1 || matches[0].Distance < matches[1].Distance {
+		sec := matches[0].Section
+		_, _ = fmt.Fprintf(notice, "Showing closest match %q for %q.\n", slugToArgs(sec.Slug), topic)
+		return sec, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("topic not found: " + topic + "\n\nDid you mean:")
+	for i, m := range matches {
+		if i == maxSuggestions {
+			break
+		}
+		sb.WriteString("\n  k6 x docs " + commandArgs(idx, m.Section.Slug))
+	}
+	return nil, errors.New(sb.String())
+}
@@ -0,0 +1,102 @@
+package docs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"click", "click", 0},
+		{"clik", "click", 1},
+		{"", "abc", 3},
+		{"post", "pots", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			t.Parallel()
+			if got := levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{"javascript-api/k6-http/get", "http get"},
+		{"javascript-api/k6-http/k6-http-get", "http get"},
+		{"javascript-api/k6-http/cookiejar/cookiejar-clear", "http cookiejar clear"},
+		{"using-k6/scenarios", "using-k6 scenarios"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			t.Parallel()
+			if got := displayArgs(tt.slug); got != tt.want {
+				t.Errorf("displayArgs(%q) = %q, want %q", tt.slug, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyResolution(t *testing.T) {
+	t.Parallel()
+
+	afs, cacheDir := setupTestdataCache(t)
+	gs := newTestGlobalState(t, afs)
+
+	run := func(t *testing.T, args ...string) (string, string, error) {
+		t.Helper()
+		cmd := newCmd(gs)
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SilenceErrors = true
+		cmd.SetArgs(append([]string{"--cache-dir", cacheDir, "--version", "v0.55.x"}, args...))
+		err := cmd.Execute()
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("unique_match_opens_section", func(t *testing.T) {
+		t.Parallel()
+		out, notice, err := run(t, "http", "cookie-jar")
+		if err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+		assertGolden(t, "view/http-cookiejar.txt", out)
+		if !strings.Contains(notice, `Showing closest match "http cookiejar" for "http cookie-jar"`) {
+			t.Errorf("expected closest match notice on stderr, got: %q", notice)
+		}
+	})
+	t.Run("typo_in_category_path", func(t *testing.T) {
+		t.Parallel()
+		out, _, err := run(t, "using-k6", "scenaros")
+		if err != nil {
+			t.Fatalf("cmd.Execute: %v", err)
+		}
+		assertGolden(t, "view/using-k6-scenarios.txt", out)
+	})
+	t.Run("ambiguous_lists_candidates", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := run(t, "http", "gett")
+		if err == nil {
+			t.Fatal("expected error for ambiguous topic")
+		}
+		assertGolden(t, "errors/ambiguous-topic.txt", err.Error())
+	})
+}
@@ -0,0 +1,4 @@
+// Type definitions for k6 v0.55.x.
+// Generated from the k6 documentation by: k6 x docs export dts
+
+/// <reference path="k6-http.d.ts" />
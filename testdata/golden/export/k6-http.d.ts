@@ -0,0 +1,13 @@
+// Type definitions for k6/http, k6 v0.55.x.
+// Generated from the k6 documentation by: k6 x docs export dts
+
+declare module "k6/http" {
+  export type Response = any;
+
+  /**
+   * Make an HTTP GET request.
+   * @param url Request URL (e.g. http://example.com).
+   * @param params Params object containing additional request parameters.
+   */
+  export function get(url: string, params?: object): Response;
+}
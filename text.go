@@ -0,0 +1,185 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Output formats accepted by --format.
+const (
+	formatMarkdown = "markdown"
+	formatText     = "text"
+)
+
+// checkFormat returns an error if format is not a supported output format.
+func checkFormat(format string) error {
+	switch format {
+	case formatMarkdown, formatText:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q (use %s or %s)", format, formatMarkdown, formatText)
+	}
+}
+
+// stripInline removes inline code, bold and italic markup, keeping the text.
+// Footnote references outside inline code are written as "[1]".
+func stripInline(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range reInlineCode.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(reFootnoteRef.ReplaceAllString(s[last:m[0]], "[$1]"))
+		sb.WriteString(s[m[2]:m[3]])
+		last = m[1]
+	}
+	sb.WriteString(reFootnoteRef.ReplaceAllString(s[last:], "[$1]"))
+	s = reBold.ReplaceAllString(sb.String(), "$1")
+	return reItalic.ReplaceAllString(s, "$1$2")
+}
+
+// RenderText converts transformed markdown to plain text for consumers that
+// cannot display markdown: headings, emphasis, code fences and quote markers
+// are dropped, code is indented, tables are aligned in columns and list
+// items become indented bullets. Paragraphs are not wrapped.
+func RenderText(content string) string {
+	blocks := splitBlocks(content)
+	out := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if s := textBlock(b); s != "" {
+			out = append(out, s)
+		}
+	}
+
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n\n") + "\n"
+}
+
+// textBlock converts a single markdown block to plain text.
+func textBlock(b mdBlock) string {
+	switch b.kind {
+	case blockHeading:
+		_, text := parseHeading(b.text)
+		return stripInline(text)
+	case blockCode:
+		return textCode(b.text)
+	case blockTable:
+		return textTable(b.text)
+	case blockProse:
+	}
+
+	lines := strings.Split(b.text, "\n")
+	switch {
+	case reRule.MatchString(b.text):
+		return ""
+	case strings.HasPrefix(strings.TrimSpace(lines[0]), ">"):
+		text := make([]string, 0, len(lines))
+		for _, l := range lines {
+			text = append(text, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), ">")))
+		}
+		return stripInline(strings.Join(text, " "))
+	case reListItem.MatchString(lines[0]):
+		return textList(lines)
+	case isFootnotes(lines):
+		for i, l := range lines {
+			lines[i] = stripInline(l)
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return stripInline(strings.Join(lines, " "))
+	}
+}
+
+// isFootnotes reports whether every line is a footnote definition, which
+// are printed one per line.
+func isFootnotes(lines []string) bool {
+	for _, l := range lines {
+		if !reFootnote.MatchString(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// textCode indents the body of a fenced code block, dropping the fences.
+func textCode(text string) string {
+	lines := codeBody(text)
+
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if l != "" {
+			l = "    " + l
+		}
+		out[i] = l
+	}
+	return strings.Join(out, "\n")
+}
+
+// textList renders list items as indented bullets, keeping numbers for
+// ordered lists. Continuation lines join the previous item.
+func textList(lines []string) string {
+	var out []string
+	for _, l := range lines {
+		m := reListItem.FindStringSubmatch(l)
+		if m == nil {
+			if len(out) > 0 {
+				out[len(out)-1] += " " + stripInline(strings.TrimSpace(l))
+			}
+			continue
+		}
+		marker := m[2]
+		if !strings.HasSuffix(marker, ".") {
+			marker = "•"
+		}
+		out = append(out, "  "+m[1]+marker+" "+stripInline(m[3]))
+	}
+	return strings.Join(out, "\n")
+}
+
+// textTable aligns table columns and underlines the header row with dashes.
+func textTable(text string) string {
+	var rows [][]string
+	header := -1
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if isDelimiterRow(cells) {
+			header = len(rows) - 1
+			continue
+		}
+		for i, c := range cells {
+			cells[i] = stripInline(c)
+		}
+		rows = append(rows, cells)
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, c := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], visibleWidth(c))
+		}
+	}
+
+	out := make([]string, 0, len(rows)+1)
+	for r, row := range rows {
+		cells := make([]string, len(widths))
+		for i := range widths {
+			c := ""
+			if i < len(row) {
+				c = row[i]
+			}
+			cells[i] = padRight(c, widths[i])
+		}
+		out = append(out, strings.TrimRight(strings.Join(cells, "  "), " "))
+		if r == header {
+			seps := make([]string, len(widths))
+			for i, w := range widths {
+				seps[i] = strings.Repeat("-", w)
+			}
+			out = append(out, strings.Join(seps, "  "))
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,62 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderText(t *testing.T) {
+	t.Parallel()
+
+	input := "## Title with `code`\n\n" +
+		"Some **bold** and *italic* text\nacross lines.\n\n" +
+		"> **Note:** Keep an eye on `vus`.\n> Second line.\n\n" +
+		"- first item\n  - nested `item`\n- second\n  continued\n\n" +
+		"1. one\n2. two\n\n" +
+		"---\n\n" +
+		"| Name | Type |\n| --- | --- |\n| `url` | string |\n| params | object |\n\n" +
+		"```javascript\nconst a = 1;\n\nconsole.log(a);\n```\n\n" +
+		"See the guide[^1], `re = /[^1]/` and options[^2].\n\n" +
+		"[^1]: https://grafana.com/docs/guide/\n[^2]: https://grafana.com/docs/options/\n"
+
+	want := "Title with code\n\n" +
+		"Some bold and italic text across lines.\n\n" +
+		"Note: Keep an eye on vus. Second line.\n\n" +
+		"  • first item\n    • nested item\n  • second continued\n\n" +
+		"  1. one\n  2. two\n\n" +
+		"Name    Type\n------  ------\nurl     string\nparams  object\n\n" +
+		"    const a = 1;\n\n    console.log(a);\n\n" +
+		"See the guide[1], re = /[^1]/ and options[2].\n\n" +
+		"[1]: https://grafana.com/docs/guide/\n[2]: https://grafana.com/docs/options/\n"
+
+	if got := RenderText(input); got != want {
+		t.Errorf("RenderText mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	t.Parallel()
+
+	run, runErr := setupCommand(t)
+
+	t.Run("topic", func(t *testing.T) {
+		t.Parallel()
+		assertGolden(t, "text/scenarios.txt", run(t, "using-k6", "scenarios", "--format", "text"))
+	})
+
+	t.Run("search", func(t *testing.T) {
+		t.Parallel()
+		out := run(t, "search", "scenarios", "--format", "text")
+		if strings.Contains(out, "#") || strings.Contains(out, "`") {
+			t.Errorf("expected no markdown syntax, got:\n%s", out)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		err := runErr(t, "http", "--format", "html")
+		if err == nil || !strings.Contains(err.Error(), `unsupported format "html"`) {
+			t.Errorf("expected unsupported format error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,300 @@
+package docs
+
+import "strings"
+
+// tokenKind is the kind of a markdown token.
+type tokenKind int
+
+const (
+	tokText        tokenKind = iota // prose without markup the transforms rewrite
+	tokFence                        // fenced code block, fence lines included
+	tokCode                         // inline code span, backticks included
+	tokShortcode                    // Hugo shortcode tag, {{< name args >}}
+	tokComponent                    // MDX component tag, <Name args>
+	tokComment                      // HTML comment
+	tokBreak                        // <br> tag
+	tokImage                        // ![alt](dest)
+	tokLink                         // [text](dest)
+	tokFrontmatter                  // YAML frontmatter at the start of a document
+)
+
+// token is a piece of a markdown document. The text of a document is the
+// raw text of its tokens, in order.
+type token struct {
+	kind       tokenKind
+	start, end int    // the token is the source text [start:end]
+	raw        string // the source text of the token
+	// Shortcodes and components: the tag name, e.g. "admonition", and its
+	// arguments, e.g. `type="note"`.
+	name, args           string
+	closing, selfClosing bool
+	// Links and images: the link text or alt text, and the destination.
+	text, dest string
+	// Fenced code: the opening fence line, the code and the closing fence
+	// line, if any, each with its line break.
+	open, body, close string
+}
+
+// lexMarkdown splits s into tokens in one scan. Fenced code blocks and
+// inline code spans are single tokens, so that nothing inside them is taken
+// for markup. Frontmatter and fences are only recognized in a document;
+// inline text, such as the text of a link, holds inline tokens only.
+//
+// Tags do not span blank lines, so that a stray "<Word" or "{{<" in prose
+// cannot swallow the paragraphs after it.
+func lexMarkdown(s string, inline bool) []token {
+	l := lexer{src: s}
+	i := 0
+	if !inline {
+		if n := frontmatterLen(s); n > 0 {
+			l.emit(token{kind: tokFrontmatter}, 0, n)
+			i = n
+		}
+	}
+	lineStart := !inline
+	for i < len(s) {
+		if lineStart {
+			if tok, n := lexFence(s[i:]); n > 0 {
+				l.emit(tok, i, i+n)
+				i += n
+				continue
+			}
+		}
+		j := strings.IndexAny(s[i:], "`{<![\n")
+		if j < 0 {
+			l.text(i, len(s))
+			break
+		}
+		j += i
+		l.text(i, j)
+		lineStart = s[j] == '\n' && !inline
+		tok, n := lexInline(s, j)
+		if n == 0 {
+			l.text(j, j+1)
+			i = j + 1
+			continue
+		}
+		if tok.kind == tokText {
+			l.text(j, j+n)
+		} else {
+			l.emit(tok, j, j+n)
+		}
+		i = j + n
+	}
+	return l.toks
+}
+
+// lexer collects the tokens of src.
+type lexer struct {
+	src  string
+	toks []token
+}
+
+// emit adds tok, the source text [start:end].
+func (l *lexer) emit(tok token, start, end int) {
+	tok.start, tok.end, tok.raw = start, end, l.src[start:end]
+	l.toks = append(l.toks, tok)
+}
+
+// text adds the source text [start:end] as prose, extending the previous
+// token if it is prose that ends at start.
+func (l *lexer) text(start, end int) {
+	if start == end {
+		return
+	}
+	if n := len(l.toks); n > 0 && l.toks[n-1].kind == tokText && l.toks[n-1].end == start {
+		last := &l.toks[n-1]
+		last.end, last.raw = end, l.src[last.start:end]
+		return
+	}
+	l.emit(token{kind: tokText}, start, end)
+}
+
+// lexInline returns the inline token at s[i:] and its length, or 0 if there
+// is none.
+func lexInline(s string, i int) (token, int) {
+	switch rest := s[i:]; rest[0] {
+	case '`':
+		if n := codeSpanLen(rest); n > 0 {
+			return token{kind: tokCode}, n
+		}
+		// A run of backticks that is not closed is prose.
+		return token{kind: tokText}, backtickRun(rest, 0)
+	case '{':
+		return lexShortcode(rest)
+	case '<':
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			if end := strings.Index(rest[len("<!--"):], "-->"); end >= 0 {
+				return token{kind: tokComment}, len("<!--") + end + len("-->")
+			}
+		case strings.HasPrefix(rest, "<br"):
+			return token{kind: tokBreak}, breakLen(rest)
+		default:
+			return lexComponent(rest)
+		}
+	case '!':
+		if text, dest, n := parseLink(rest[1:], false); n > 0 {
+			return token{kind: tokImage, text: text, dest: dest}, n + 1
+		}
+	case '[':
+		// A link after "!" is an image that did not parse, e.g. one with
+		// brackets in its alt text; it is left alone.
+		if i > 0 && s[i-1] == '!' {
+			break
+		}
+		if text, dest, n := parseLink(rest, true); n > 0 {
+			return token{kind: tokLink, text: text, dest: dest}, n
+		}
+	}
+	return token{}, 0
+}
+
+// frontmatterLen returns the length of the YAML frontmatter at the start of
+// s, as removed by StripFrontmatter, or 0 if s has none.
+func frontmatterLen(s string) int {
+	return len(s) - len(StripFrontmatter(s))
+}
+
+// lexFence returns the fenced code block that starts s and its length, or
+// 0 if the first line of s is not an opening fence. A block without a
+// closing fence runs to the end of s.
+func lexFence(s string) (token, int) {
+	line, rest, _ := strings.Cut(s, "\n")
+	f, ok := openFence(line)
+	if !ok {
+		return token{}, 0
+	}
+	tok := token{kind: tokFence, open: s[:min(len(line)+1, len(s))]}
+	end := len(tok.open)
+	for end < len(s) {
+		line, rest, _ = strings.Cut(rest, "\n")
+		next := min(end+len(line)+1, len(s))
+		if f.closes(line) {
+			tok.body, tok.close = s[len(tok.open):end], s[end:next]
+			return tok, next
+		}
+		end = next
+	}
+	tok.body = s[len(tok.open):]
+	return tok, len(s)
+}
+
+// codeSpanLen returns the length of the code span that starts s: a run of
+// backticks up to the next run of the same length on the same line. It
+// returns 0 if the run is not closed.
+func codeSpanLen(s string) int {
+	n := backtickRun(s, 0)
+	line := s
+	if eol := strings.IndexByte(s, '\n'); eol >= 0 {
+		line = s[:eol]
+	}
+	for j := n; j < len(line); {
+		if line[j] != '`' {
+			j++
+			continue
+		}
+		m := backtickRun(line, j)
+		if m == n {
+			return j + m
+		}
+		j += m
+	}
+	return 0
+}
+
+// breakLen returns the length of the <br>, <br/> or <br /> tag that starts
+// s, or 0.
+func breakLen(s string) int {
+	i := len("<br")
+	for i < len(s) && strings.IndexByte(" \t\n\f\r", s[i]) >= 0 {
+		i++
+	}
+	if i < len(s) && s[i] == '/' {
+		i++
+	}
+	if i < len(s) && s[i] == '>' {
+		return i + 1
+	}
+	return 0
+}
+
+// lexShortcode returns the shortcode tag that starts s, e.g.
+// {{< admonition type="note" >}}, and its length, or 0.
+func lexShortcode(s string) (token, int) {
+	if !strings.HasPrefix(s, "{{<") {
+		return token{}, 0
+	}
+	end := strings.Index(s, ">}}")
+	if end < 0 || strings.Contains(s[:end], "\n\n") {
+		return token{}, 0
+	}
+	inner, closing := strings.CutPrefix(strings.TrimSpace(s[len("{{<"):end]), "/")
+	tok := token{kind: tokShortcode, closing: closing}
+	if fields := strings.Fields(inner); len(fields) > 0 {
+		tok.name, tok.args = fields[0], strings.Join(fields[1:], " ")
+	}
+	return tok, end + len(">}}")
+}
+
+// lexComponent returns the MDX component tag that starts s and its length,
+// or 0. Component names are PascalCase, like <Glossary> or
+// </DescriptionList>, which tells them from HTML tags.
+func lexComponent(s string) (token, int) {
+	tok := token{kind: tokComponent}
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		tok.closing = true
+		i++
+	}
+	if i+1 >= len(s) || s[i] < 'A' || s[i] > 'Z' || s[i+1] < 'a' || s[i+1] > 'z' {
+		return token{}, 0
+	}
+	name := i
+	for i < len(s) && isWordByte(s[i]) {
+		i++
+	}
+	end := strings.IndexByte(s[i:], '>')
+	if end < 0 || strings.Contains(s[i:i+end], "\n\n") {
+		return token{}, 0
+	}
+	tok.name = s[name:i]
+	args, selfClosing := strings.CutSuffix(strings.TrimSpace(s[i:i+end]), "/")
+	tok.args, tok.selfClosing = strings.TrimSpace(args), selfClosing
+	return tok, i + end + 1
+}
+
+// isWordByte reports whether c is an ASCII letter, digit or underscore.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parseLink parses the "[text](dest)" that starts s and returns the text,
+// the destination and the length, or 0. With nested, the text may hold one
+// level of brackets, as in [get(url, [params])](url); otherwise it holds
+// no closing bracket, as the alt text of an image.
+func parseLink(s string, nested bool) (string, string, int) {
+	if s == "" || s[0] != '[' {
+		return "", "", 0
+	}
+	i := 1
+	for ; i < len(s) && s[i] != ']'; i++ {
+		if s[i] != '[' || !nested {
+			continue
+		}
+		end := strings.IndexByte(s[i+1:], ']')
+		if end < 0 {
+			return "", "", 0
+		}
+		i += end + 1
+	}
+	if i+1 >= len(s) || s[i+1] != '(' {
+		return "", "", 0
+	}
+	text := s[1:i]
+	end := strings.IndexByte(s[i+2:], ')')
+	if end <= 0 {
+		return "", "", 0
+	}
+	return text, s[i+2 : i+2+end], i + 2 + end + 1
+}
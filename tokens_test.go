@@ -0,0 +1,109 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLexMarkdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		inline bool
+		want   []tokenKind
+	}{
+		{
+			name:  "prose",
+			input: "Plain text, 1 < 2 and [not a link].\n",
+			want:  []tokenKind{tokText},
+		},
+		{
+			name:  "frontmatter and fence",
+			input: "---\ntitle: x\n---\n```js\nlet a = [1](2); // <br>\n```\nAfter\n",
+			want:  []tokenKind{tokFrontmatter, tokFence, tokText},
+		},
+		{
+			name:   "inline text has no frontmatter or fences",
+			input:  "---\n```js\n",
+			inline: true,
+			want:   []tokenKind{tokText},
+		},
+		{
+			name:  "inline code",
+			input: "Call `get([url])` or ``a ` b``, not ``` alone.",
+			want:  []tokenKind{tokText, tokCode, tokText, tokCode, tokText},
+		},
+		{
+			name:  "tags",
+			input: "{{< section >}}<Glossary>a<br/>b</Glossary><!-- c -->",
+			want: []tokenKind{
+				tokShortcode, tokComponent, tokText, tokBreak, tokText, tokComponent, tokComment,
+			},
+		},
+		{
+			name:  "links and images",
+			input: "![alt](a.png) [get( url, [params] )](u) ![a [b]](x)",
+			want:  []tokenKind{tokImage, tokText, tokLink, tokText},
+		},
+		{
+			name:  "tag across a blank line",
+			input: "Press <Ctrl\n\nthen > or {{< x\n\n>}}",
+			want:  []tokenKind{tokText},
+		},
+		{
+			name:  "unterminated fence",
+			input: "Text\n```\ncode <br>\n",
+			want:  []tokenKind{tokText, tokFence},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			toks := lexMarkdown(tt.input, tt.inline)
+			var kinds []tokenKind
+			var raw strings.Builder
+			for _, tok := range toks {
+				kinds = append(kinds, tok.kind)
+				raw.WriteString(tok.raw)
+			}
+			if len(kinds) != len(tt.want) {
+				t.Fatalf("kinds = %v, want %v", kinds, tt.want)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.want[i] {
+					t.Fatalf("kinds = %v, want %v", kinds, tt.want)
+				}
+			}
+			if raw.String() != tt.input {
+				t.Errorf("tokens = %q, want %q", raw.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestLexMarkdown_Fields(t *testing.T) {
+	t.Parallel()
+
+	toks := lexMarkdown("{{< /admonition >}}<Card title=\"x\" />[a [b]](https://x.io \"t\")", false)
+	if len(toks) != 3 {
+		t.Fatalf("got %d tokens", len(toks))
+	}
+	if tag := toks[0]; tag.name != "admonition" || !tag.closing {
+		t.Errorf("shortcode = %+v", tag)
+	}
+	if tag := toks[1]; tag.name != "Card" || tag.args != `title="x"` || !tag.selfClosing {
+		t.Errorf("component = %+v", tag)
+	}
+	if link := toks[2]; link.text != "a [b]" || link.dest != `https://x.io "t"` {
+		t.Errorf("link = %+v", link)
+	}
+
+	fence := lexMarkdown("````md\n```js\nx\n```\n````\nafter", false)[0]
+	if fence.open != "````md\n" || fence.body != "```js\nx\n```\n" || fence.close != "````\n" {
+		t.Errorf("fence = %q %q %q", fence.open, fence.body, fence.close)
+	}
+}
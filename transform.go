@@ -2,33 +2,22 @@ package docs
 
 import (
 	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 var (
-	reShared     = regexp.MustCompile(`\{\{<\s*docs/shared\s+source="k6"\s+lookup="([^"]+)".*?>\}\}`)
-	reCodeTag    = regexp.MustCompile(`\{\{<\s*/?\s*code\s*>\}\}`)
-	reAdmonition = regexp.MustCompile(
+	reShared = regexp.MustCompile(`\{\{<\s*docs/shared\s+source="k6"\s+lookup="([^"]+)".*?>\}\}`)
+	// reAdmonitionType matches the type argument of an admonition tag.
+	reAdmonitionType = regexp.MustCompile(`\btype="([^"]+)"`)
+	reAdmonition     = regexp.MustCompile(
 		`(?s)\{\{<\s*admonition\s+type="([^"]+)"\s*>\}\}\s*\n(.*?)\n\s*\{\{<\s*/admonition\s*>\}\}`,
 	)
-	reSection      = regexp.MustCompile(`\{\{<\s*/?\s*section\b[^>]*>\}\}`)
-	reAnyShortcode = regexp.MustCompile(`\{\{<\s*/?\s*[^>]+>\}\}`)
-	reComponentTag = regexp.MustCompile(`</?[A-Z][a-z][a-zA-Z]*[^>]*>`) // <Glossary>, </DescriptionList>, etc.
-	reBrTag        = regexp.MustCompile(`<br\s*/?>`)                    // <br/>, <br />, <br>
-	reHTMLComment  = regexp.MustCompile(`<!--[\s\S]*?-->`)
-	reExtraNewline = regexp.MustCompile(`\n{3,}`)
-
-	// reImageLink matches markdown image links: ![alt](url)
-	reImageLink = regexp.MustCompile(`!\[([^\]]*)\]\([^)]+\)`)
 	// reMarkdownLink matches markdown links: [text](url)
 	// The text portion allows one level of nested brackets for cases like [get(url, [params])](url).
-	reMarkdownLink = regexp.MustCompile(`\[((?:[^\[\]]|\[[^\]]*\])*)\]\([^)]+\)`)
-
-	// reInternalLink matches markdown links pointing to Grafana k6 docs.
-	// Link text may contain brackets (e.g., "get(url, [params])"), so we
-	// match greedily up to "](https://grafana.com/docs/k6/".
-	// Captures: [1]=link text, [2]=path after /docs/k6/vX.Y.Z/
-	reInternalLink = regexp.MustCompile(`\[((?:[^\[\]]|\[[^\]]*\])*)\]\(https://grafana\.com/docs/k6/v[^/]+/([^)]*)\)`)
+	// Captures: [1]=link text, [2]=destination
+	reMarkdownLink = regexp.MustCompile(`\[((?:[^\[\]]|\[[^\]]*\])*)\]\(([^)]+)\)`)
 )
 
 // PrepareTransform resolves docs/shared shortcodes using the shared content
@@ -52,109 +41,435 @@ func PrepareTransform(content string, sharedContent map[string]string) string {
 	})
 }
 
-// Transform applies markdown cleanup to content. It handles all pure text
-// transforms (shortcode stripping, admonition conversion, link stripping,
-// frontmatter removal, whitespace normalization). The pipeline runs in a
-// fixed order:
-//  1. Strip code tags
-//  2. Convert admonitions to blockquotes
-//  3. Strip section tags
-//  4. Strip remaining shortcodes
-//     4a. Strip React/MDX component tags (PascalCase)
-//     4b. Strip <br/> tags
-//  5. Replace <K6_VERSION> with version
-//  6. Convert internal docs links to plain text
-//     6a. Strip remaining markdown image links
-//     6b. Strip remaining markdown links
-//  7. Strip HTML comments
-//  8. Strip YAML frontmatter
-//  9. Normalize whitespace
+// SharedLookups returns the lookups of the docs/shared shortcodes in
+// content, e.g. "javascript-api/k6-http.md", in order of appearance.
+func SharedLookups(content string) []string {
+	var lookups []string
+	for _, m := range reShared.FindAllStringSubmatch(content, -1) {
+		lookups = append(lookups, m[1])
+	}
+	return lookups
+}
+
+// InternalLinkPaths returns the paths of the links in content that point to
+// docs pages included in the bundle, e.g. "javascript-api/k6-http/get",
+// without anchors or trailing slashes. Links in code are ignored.
+func InternalLinkPaths(content string) []string {
+	var paths []string
+	for _, tok := range lexMarkdown(content, false) {
+		if tok.kind != tokLink {
+			continue
+		}
+		path, ok := internalDocsPath(strings.ReplaceAll(tok.dest, "<K6_VERSION>", "v0"))
+		if ok && IsIncludedDocsPath(path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// internalDocsPath returns the path of a link destination under
+// https://grafana.com/docs/k6/<version>/, e.g. "javascript-api/k6-http/get",
+// without anchor or trailing slash, and whether it is one.
+func internalDocsPath(dest string) (string, bool) {
+	rest, ok := strings.CutPrefix(dest, "https://grafana.com/docs/k6/v")
+	if !ok {
+		return "", false
+	}
+	version, path, ok := strings.Cut(rest, "/")
+	if !ok || version == "" {
+		return "", false
+	}
+	path, _, _ = strings.Cut(path, "#")
+	return strings.TrimRight(path, "/"), true
+}
+
+// Transform applies markdown cleanup to content with the default pipeline.
+// It handles all pure text transforms (shortcode stripping, admonition
+// conversion, link stripping, frontmatter removal, whitespace normalization)
+// in the order of [DefaultPipeline]. Fenced code blocks and inline code are
+// left untouched, except for the version placeholder.
 func Transform(content, version string) string {
-	if content == "" {
-		return ""
+	return DefaultPipeline().Apply(content, version)
+}
+
+// DefaultPipeline returns the transform stages applied at runtime, in a
+// fixed order. All are enabled except inline-links:
+//  1. code-tabs: label code tab alternatives and strip code tags
+//  2. admonitions: convert admonitions to blockquotes
+//  3. sections: strip section tags
+//  4. shortcodes: strip remaining shortcodes
+//  5. components: convert known React/MDX components, strip the others
+//  6. line-breaks: strip <br/> tags
+//  7. version: replace <K6_VERSION> with version
+//  8. internal-links: convert internal docs links to plain text
+//  9. images: strip markdown image links, keeping the alt text
+//  10. inline-links: write external URLs inline, "text (url)"
+//  11. links: strip remaining markdown links, keeping the link text and
+//     collecting external URLs into numbered footnotes
+//  12. comments: strip HTML comments
+//  13. frontmatter: strip YAML frontmatter
+//  14. whitespace: normalize whitespace
+//
+// The stages do not make a pass over the document each. It is split into
+// tokens once, fenced and inline code included, and every enabled stage
+// rewrites the tokens it handles as they are written out, so that examples
+// containing links, tags, shortcodes or template strings are never altered.
+// Only version, which also rewrites code, sees inside code tokens.
+func DefaultPipeline() *Pipeline {
+	p := NewPipeline(
+		builtinStage{"code-tabs", "Label code tab alternatives and strip code tags", stageCodeTabs},
+		builtinStage{"admonitions", "Convert admonitions to blockquotes", stageAdmonitions},
+		builtinStage{"sections", "Strip section shortcodes", stageSections},
+		builtinStage{"shortcodes", "Strip remaining shortcodes", stageShortcodes},
+		builtinStage{"components", "Convert known MDX components to markdown, strip the others", stageComponents},
+		builtinStage{"line-breaks", "Strip <br/> tags", stageLineBreaks},
+		builtinStage{"version", "Replace <K6_VERSION> with the docs version", stageVersion},
+		builtinStage{"internal-links", "Convert links to included docs pages to plain text", stageInternalLinks},
+		builtinStage{"images", "Replace images with their alt text", stageImages},
+		builtinStage{"inline-links", "Write the URLs of external links inline, after the link text", stageInlineLinks},
+		builtinStage{"links", "Replace remaining links with their text, external URLs as footnotes", stageLinks},
+		builtinStage{"comments", "Strip HTML comments", stageComments},
+		builtinStage{"frontmatter", "Strip YAML frontmatter", stageFrontmatter},
+		builtinStage{"whitespace", "Collapse runs of blank lines", stageWhitespace},
+	)
+	p.disabled["inline-links"] = true
+	return p
+}
+
+// builtin is a set of the stages of [DefaultPipeline].
+type builtin uint16
+
+const (
+	stageCodeTabs builtin = 1 << iota
+	stageAdmonitions
+	stageSections
+	stageShortcodes
+	stageComponents
+	stageLineBreaks
+	stageVersion
+	stageInternalLinks
+	stageImages
+	stageInlineLinks
+	stageLinks
+	stageComments
+	stageFrontmatter
+	stageWhitespace
+)
+
+// builtinStage is a stage of [DefaultPipeline]. Consecutive built-in stages
+// run together, in a single transform of the document.
+type builtinStage struct {
+	name, description string
+	stage             builtin
+}
+
+func (s builtinStage) Name() string        { return s.name }
+func (s builtinStage) Description() string { return s.description }
+func (s builtinStage) Apply(content, version string) string {
+	return transform(content, version, s.stage)
+}
+
+func (s builtinStage) Scope() StageScope {
+	if s.stage == stageVersion {
+		return ScopeAll
 	}
+	return ScopeProse
+}
 
-	s := content
+// transform runs the built-in stages in stages over content: it splits
+// content into tokens and writes them out, rewritten by the stages that
+// handle them, in one pass. The footnotes of the links stage are appended
+// at the end.
+func transform(content, version string, stages builtin) string {
+	t := &transformer{stages: stages, version: version}
+	w := t.writer()
+	t.render(w, lexMarkdown(content, false), nil)
+	out := string(w.buf)
+	if len(t.urls) == 0 {
+		return out
+	}
 
-	// 1. Strip code tags (keep content between them).
-	s = reCodeTag.ReplaceAllString(s, "")
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(out, "\n"))
+	sb.WriteString("\n\n")
+	for i, u := range t.urls {
+		sb.WriteString(footnoteRef(i+1) + ": " + u + "\n")
+	}
+	return sb.String()
+}
 
-	// 3. Convert admonitions to blockquotes.
-	s = reAdmonition.ReplaceAllStringFunc(s, func(match string) string {
-		m := reAdmonition.FindStringSubmatch(match)
-		if m == nil {
-			return match
-		}
-		title := strings.ToUpper(m[1][:1]) + m[1][1:]
-		body := strings.TrimSpace(m[2])
-
-		lines := strings.Split(body, "\n")
-		var sb strings.Builder
-		first := true
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
+// transformer holds the state of a transform.
+type transformer struct {
+	stages  builtin
+	version string
+	urls    []string // the URLs of the footnotes written by the links stage
+	lists   int      // the depth of definition list components
+}
+
+// on reports whether stage runs.
+func (t *transformer) on(stage builtin) bool {
+	return t.stages&stage != 0
+}
+
+// writer returns a writer for the output of t.
+func (t *transformer) writer() *mdWriter {
+	return &mdWriter{collapse: t.on(stageWhitespace)}
+}
+
+// versioned replaces the version placeholder in s, prose or code.
+func (t *transformer) versioned(s string) string {
+	if !t.on(stageVersion) {
+		return s
+	}
+	return strings.ReplaceAll(s, "<K6_VERSION>", t.version)
+}
+
+// render writes toks to w. In a code group, group labels its fenced blocks.
+func (t *transformer) render(w *mdWriter, toks []token, group *codeGroup) {
+	for i := 0; i < len(toks); i++ {
+		tok := &toks[i]
+		switch tok.kind {
+		case tokText:
+			w.prose(t.prose(tok.raw))
+		case tokFence:
+			if group != nil {
+				group.label(w)
 			}
-			if first {
-				sb.WriteString("> **" + title + ":** " + line + "\n")
-				first = false
+			w.prose(t.versioned(tok.open))
+			w.code(t.versioned(tok.body), true)
+			w.prose(t.versioned(tok.close))
+		case tokCode:
+			w.code(t.versioned(tok.raw), false)
+		case tokShortcode:
+			i = t.shortcode(w, toks, i)
+		case tokComponent:
+			i = t.component(w, toks, i)
+		case tokComment:
+			t.strip(w, tok, stageComments)
+		case tokBreak:
+			t.strip(w, tok, stageLineBreaks)
+		case tokFrontmatter:
+			t.strip(w, tok, stageFrontmatter)
+		case tokImage:
+			if t.on(stageImages) {
+				w.prose(t.inline(tok.text))
 			} else {
-				sb.WriteString("> " + line + "\n")
+				w.prose(t.versioned(tok.raw))
 			}
+		case tokLink:
+			w.prose(t.link(t.inline(tok.text), t.versioned(tok.dest)))
 		}
-		return sb.String()
-	})
+	}
+}
 
-	// 4. Strip section tags.
-	s = reSection.ReplaceAllString(s, "")
+// prose returns a run of prose as written out.
+func (t *transformer) prose(s string) string {
+	s = t.versioned(s)
+	if t.lists > 0 {
+		s = definitions(s)
+	}
+	return s
+}
 
-	// 5. Strip remaining shortcodes.
-	s = reAnyShortcode.ReplaceAllString(s, "")
+// inline returns inline text, such as the text of a link, as written out.
+func (t *transformer) inline(s string) string {
+	w := t.writer()
+	t.render(w, lexMarkdown(s, true), nil)
+	return string(w.buf)
+}
 
-	// 5a. Strip React/MDX component tags (PascalCase like <Glossary>, <DescriptionList>).
-	s = reComponentTag.ReplaceAllString(s, "")
+// strip drops tok if stage runs, and writes it unchanged otherwise.
+func (t *transformer) strip(w *mdWriter, tok *token, stage builtin) {
+	if !t.on(stage) {
+		w.prose(t.versioned(tok.raw))
+	}
+}
 
-	// 5b. Strip <br/> tags.
-	s = reBrTag.ReplaceAllString(s, "")
+// shortcode writes the shortcode tag toks[i] and returns the index of the
+// last token it used: code and admonition tags take the tokens up to their
+// closing tag.
+func (t *transformer) shortcode(w *mdWriter, toks []token, i int) int {
+	switch tok := &toks[i]; {
+	case tok.name == "code" && t.on(stageCodeTabs):
+		return t.block(w, toks, i, t.codeTabs)
+	case tok.name == "admonition" && t.on(stageAdmonitions):
+		return t.block(w, toks, i, t.admonition)
+	case tok.name == "section" && t.on(stageSections), t.on(stageShortcodes):
+		// Stripped.
+	default:
+		w.prose(t.versioned(tok.raw))
+	}
+	return i
+}
 
-	// 6. Replace version placeholder.
-	s = strings.ReplaceAll(s, "<K6_VERSION>", version)
+// block writes the tokens between the shortcode tag toks[i] and its closing
+// tag, the next tag of the same name, with fn. Tags without a partner are
+// dropped. It returns the index of the closing tag.
+func (t *transformer) block(w *mdWriter, toks []token, i int, fn func(w *mdWriter, open *token, body []token)) int {
+	open := &toks[i]
+	if open.closing {
+		return i
+	}
+	for j := i + 1; j < len(toks); j++ {
+		if toks[j].kind != tokShortcode || toks[j].name != open.name {
+			continue
+		}
+		if !toks[j].closing {
+			return i
+		}
+		fn(w, open, toks[i+1:j])
+		return j
+	}
+	return i
+}
 
-	// 7. Convert internal docs links to plain text.
-	// Links pointing to categories we ship become just the link text.
-	// Links to excluded categories (extensions, set-up, etc.) keep the URL.
-	s = reInternalLink.ReplaceAllStringFunc(s, func(match string) string {
-		m := reInternalLink.FindStringSubmatch(match)
-		if m == nil {
-			return match
+// codeTabs writes the body of a code tag, labelling its alternatives.
+func (t *transformer) codeTabs(w *mdWriter, _ *token, body []token) {
+	var langs []string
+	for _, tok := range body {
+		if tok.kind == tokFence {
+			langs = append(langs, fenceLang(tok.open))
 		}
-		linkText, path := m[1], m[2]
-		// Strip trailing slash and anchor.
-		clean := strings.SplitN(path, "#", 2)[0]
-		clean = strings.TrimRight(clean, "/")
-		if IsIncludedDocsPath(clean) {
-			return linkText
+	}
+	t.render(w, body, &codeGroup{labels: alternativeLabels(langs), start: len(w.buf)})
+}
+
+// codeGroup labels the fenced blocks of a code tag as they are written.
+type codeGroup struct {
+	labels []string // the labels of the blocks not written yet
+	start  int      // the offset of the group in the output
+}
+
+// label writes the label of the next block of g, on a paragraph of its own.
+func (g *codeGroup) label(w *mdWriter) {
+	if len(g.labels) == 0 {
+		return
+	}
+	label := g.labels[0]
+	g.labels = g.labels[1:]
+
+	// The line before the block, if it is in the group, must be blank.
+	out := string(w.buf[g.start:])
+	if eol := strings.LastIndexByte(out, '\n'); eol >= 0 {
+		prev := out[strings.LastIndexByte(out[:eol], '\n')+1 : eol]
+		if strings.TrimSpace(prev) != "" {
+			w.prose("\n")
 		}
-		return match
-	})
+	}
+	w.prose("**Option: " + label + "**\n\n")
+}
 
-	// 7a. Strip remaining markdown image links, keeping alt text.
-	s = reImageLink.ReplaceAllString(s, "$1")
+// admonition writes the body of an admonition tag as a blockquote with a
+// bold title. Code keeps its lines; blank lines of prose are dropped. An
+// admonition without a type is written as is.
+func (t *transformer) admonition(w *mdWriter, open *token, body []token) {
+	m := reAdmonitionType.FindStringSubmatch(open.args)
+	if m == nil {
+		t.render(w, body, nil)
+		return
+	}
+	title := strings.ToUpper(m[1][:1]) + m[1][1:]
+
+	quote := t.writer()
+	t.render(quote, body, nil)
+	first := true
+	for _, line := range quote.lines() {
+		switch text := strings.TrimSpace(line.text); {
+		case line.code && line.text == "":
+			w.prose(">\n")
+		case line.code:
+			w.prose("> " + line.text + "\n")
+		case text == "":
+		case first:
+			w.prose("> **" + title + ":** " + text + "\n")
+			first = false
+		default:
+			w.prose("> " + text + "\n")
+		}
+	}
+}
 
-	// 7b. Strip remaining markdown links, keeping link text.
-	s = reMarkdownLink.ReplaceAllString(s, "$1")
+// mdWriter collects the output of a transform. It tells code from prose, so
+// that runs of blank lines are only collapsed in prose.
+type mdWriter struct {
+	buf      []byte
+	fenced   [][2]int // the offsets of the bodies of fenced code blocks in buf
+	newlines int      // the line breaks that end buf, if it ends with prose
+	collapse bool     // collapse runs of blank lines in prose
+}
+
+// prose writes s, collapsing runs of more than one blank line.
+func (w *mdWriter) prose(s string) {
+	if !w.collapse {
+		w.buf = append(w.buf, s...)
+		return
+	}
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, s...)
+			w.newlines = 0
+			return
+		}
+		if i > 0 {
+			w.buf = append(w.buf, s[:i]...)
+			w.newlines = 0
+		}
+		if w.newlines < 2 {
+			w.buf = append(w.buf, '\n')
+		}
+		w.newlines++
+		s = s[i+1:]
+	}
+}
 
-	// 8. Strip HTML comments.
-	s = reHTMLComment.ReplaceAllString(s, "")
+// code writes s unchanged. The lines of a fenced block are kept apart from
+// prose when the output is split into lines.
+func (w *mdWriter) code(s string, fenced bool) {
+	if fenced && s != "" {
+		w.fenced = append(w.fenced, [2]int{len(w.buf), len(w.buf) + len(s)})
+	}
+	w.buf = append(w.buf, s...)
+	w.newlines = 0
+}
 
-	// 9. Strip YAML frontmatter.
-	s = StripFrontmatter(s)
+// include writes the output of c without leading and trailing whitespace.
+func (w *mdWriter) include(c *mdWriter) {
+	out := strings.TrimRightFunc(string(c.buf), unicode.IsSpace)
+	trimmed := strings.TrimLeftFunc(out, unicode.IsSpace)
+	lead := len(out) - len(trimmed)
+	for _, r := range c.fenced {
+		if from, to := max(r[0], lead), min(r[1], len(out)); from < to {
+			w.fenced = append(w.fenced, [2]int{len(w.buf) + from - lead, len(w.buf) + to - lead})
+		}
+	}
+	w.buf = append(w.buf, trimmed...)
+	w.newlines = 0
+}
 
-	// 10. Normalize whitespace: collapse 3+ consecutive newlines to 2.
-	s = reExtraNewline.ReplaceAllString(s, "\n\n")
+// mdLine is a line of output and whether it is in a fenced code block.
+type mdLine struct {
+	text string
+	code bool
+}
 
-	return s
+// lines returns the lines of the output without leading and trailing
+// whitespace.
+func (w *mdWriter) lines() []mdLine {
+	out := strings.TrimRightFunc(string(w.buf), unicode.IsSpace)
+	trimmed := strings.TrimLeftFunc(out, unicode.IsSpace)
+	if trimmed == "" {
+		return nil
+	}
+	offset := len(out) - len(trimmed)
+	lines := make([]mdLine, 0, strings.Count(trimmed, "\n")+1)
+	for text := range strings.SplitSeq(trimmed, "\n") {
+		code := slices.ContainsFunc(w.fenced, func(r [2]int) bool { return r[0] <= offset && offset < r[1] })
+		lines = append(lines, mdLine{text: text, code: code})
+		offset += len(text) + 1
+	}
+	return lines
 }
 
 // StripFrontmatter removes YAML frontmatter (delimited by "---") from the
@@ -1,6 +1,7 @@
 package docs
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -121,6 +122,25 @@ func TestPrepareTransform_Empty(t *testing.T) {
 	}
 }
 
+func TestInternalLinkPaths(t *testing.T) {
+	t.Parallel()
+
+	content := "{{< docs/shared source=\"k6\" lookup=\"javascript-api/k6-http.md\" version=\"<K6_VERSION>\" >}}\n\n" +
+		"See [get](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/get/#returns),\n" +
+		"[install](https://grafana.com/docs/k6/<K6_VERSION>/set-up/install-k6/) and\n" +
+		"[checks](https://grafana.com/docs/k6/v1.5.0/using-k6/checks).\n\n" +
+		"```js\n// [del](https://grafana.com/docs/k6/<K6_VERSION>/javascript-api/k6-http/del/)\n```\n"
+
+	if got, want := SharedLookups(content), []string{"javascript-api/k6-http.md"}; !slices.Equal(got, want) {
+		t.Errorf("SharedLookups = %q, want %q", got, want)
+	}
+	// Excluded pages and links in code are left out.
+	want := []string{"javascript-api/k6-http/get", "using-k6/checks"}
+	if got := InternalLinkPaths(content); !slices.Equal(got, want) {
+		t.Errorf("InternalLinkPaths = %q, want %q", got, want)
+	}
+}
+
 func TestTransform_StripCodeTags(t *testing.T) {
 	t.Parallel()
 
@@ -204,6 +224,16 @@ Line three.
 			content: "Before\n\n{{< admonition type=\"note\" >}}\n\nImportant thing.\n\n{{< /admonition >}}\n\nAfter",
 			want:    "Before\n\n> **Note:** Important thing.\n\nAfter",
 		},
+		{
+			name:    "admonition with code",
+			content: "{{< admonition type=\"tip\" >}}\n\nRun:\n\n```js\nif (a) {\n  b();\n}\n```\n\n{{< /admonition >}}",
+			want:    "> **Tip:** Run:\n> ```js\n> if (a) {\n>   b();\n> }\n> ```\n",
+		},
+		{
+			name:    "admonition with blank and indented code lines",
+			content: "{{< admonition type=\"tip\" >}}\n```js\n  a();\n\n\n\n  b();\n```\n{{< /admonition >}}",
+			want:    "> **Tip:** ```js\n>   a();\n>\n>\n>\n>   b();\n> ```\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -313,10 +343,10 @@ func TestTransform_ReplaceVersion(t *testing.T) {
 			want:    "Visit https://grafana.com/docs/k6/v1.5.x/extensions/explore for extensions.",
 		},
 		{
-			name:    "replace version in external link then strip to text",
+			name:    "replace version in external link then keep it as a footnote",
 			content: "[extensions](https://grafana.com/docs/k6/<K6_VERSION>/extensions/explore)",
 			version: "v1.5.x",
-			want:    "extensions",
+			want:    "extensions[^1]\n\n[^1]: https://grafana.com/docs/k6/v1.5.x/extensions/explore\n",
 		},
 	}
 
@@ -361,19 +391,19 @@ func TestTransform_ConvertInternalLinks(t *testing.T) {
 			want:    "scenarios",
 		},
 		{
-			name:    "excluded category link stripped to text",
+			name:    "excluded category link kept as a footnote",
 			content: "[Build a k6 binary](https://grafana.com/docs/k6/v1.5.x/extensions/build-k6-binary-using-go)",
-			want:    "Build a k6 binary",
+			want:    "Build a k6 binary[^1]\n\n[^1]: https://grafana.com/docs/k6/v1.5.x/extensions/build-k6-binary-using-go\n",
 		},
 		{
-			name:    "get-started link stripped to text",
+			name:    "get-started link kept as a footnote",
 			content: "[Install k6](https://grafana.com/docs/k6/v1.5.x/get-started/installation/)",
-			want:    "Install k6",
+			want:    "Install k6[^1]\n\n[^1]: https://grafana.com/docs/k6/v1.5.x/get-started/installation/\n",
 		},
 		{
-			name:    "set-up link stripped to text",
+			name:    "set-up link kept as a footnote",
 			content: "[Set up](https://grafana.com/docs/k6/v1.5.x/set-up/something)",
-			want:    "Set up",
+			want:    "Set up[^1]\n\n[^1]: https://grafana.com/docs/k6/v1.5.x/set-up/something\n",
 		},
 		{
 			name:    "multiple links in one line",
@@ -391,9 +421,9 @@ func TestTransform_ConvertInternalLinks(t *testing.T) {
 			want:    "check(selector[, options])",
 		},
 		{
-			name:    "non-grafana link also stripped to text",
+			name:    "non-grafana link kept as a footnote",
 			content: "[example](https://example.com/something)",
-			want:    "example",
+			want:    "example[^1]\n\n[^1]: https://example.com/something\n",
 		},
 		{
 			name:    "all included categories become plain text",
@@ -406,9 +436,9 @@ func TestTransform_ConvertInternalLinks(t *testing.T) {
 			want:    "g",
 		},
 		{
-			name:    "reference non-glossary link stripped by catch-all",
+			name:    "reference non-glossary link kept as a footnote by catch-all",
 			content: "[g](https://grafana.com/docs/k6/v1.5.x/reference/grault)",
-			want:    "g",
+			want:    "g[^1]\n\n[^1]: https://grafana.com/docs/k6/v1.5.x/reference/grault\n",
 		},
 	}
 
@@ -435,17 +465,32 @@ func TestTransform_StripMarkdownLinks(t *testing.T) {
 		{
 			name:    "simple link",
 			content: "[jslib](https://example.com)",
-			want:    "jslib",
+			want:    "jslib[^1]\n\n[^1]: https://example.com\n",
 		},
 		{
 			name:    "link with path",
 			content: "[aws](https://example.com/aws)",
-			want:    "aws",
+			want:    "aws[^1]\n\n[^1]: https://example.com/aws\n",
 		},
 		{
 			name:    "link inline with text",
-			content: "text with [a link](http://example.com) in it",
-			want:    "text with a link in it",
+			content: "text with [a link](http://example.com) in it\n",
+			want:    "text with a link[^1] in it\n\n[^1]: http://example.com\n",
+		},
+		{
+			name:    "relative link stripped to text",
+			content: "see [options](../options) and [below](#usage)",
+			want:    "see options and below",
+		},
+		{
+			name:    "link titled with its URL needs no footnote",
+			content: "[https://jslib.k6.io](https://jslib.k6.io)",
+			want:    "https://jslib.k6.io",
+		},
+		{
+			name:    "link with a title",
+			content: `[k6](https://k6.io "k6 home")`,
+			want:    "k6[^1]\n\n[^1]: https://k6.io\n",
 		},
 		{
 			name:    "image link stripped to alt text",
@@ -455,12 +500,12 @@ func TestTransform_StripMarkdownLinks(t *testing.T) {
 		{
 			name:    "nested brackets handled gracefully",
 			content: "[nested [brackets]](http://example.com)",
-			want:    "nested [brackets]",
+			want:    "nested [brackets][^1]\n\n[^1]: http://example.com\n",
 		},
 		{
-			name:    "multiple links in one line",
-			content: "[foo](http://a.com) and [bar](http://b.com)",
-			want:    "foo and bar",
+			name:    "multiple links share footnotes by URL",
+			content: "[foo](http://a.com) and [bar](http://b.com), [foo again](http://a.com)",
+			want:    "foo[^1] and bar[^2], foo again[^1]\n\n[^1]: http://a.com\n[^2]: http://b.com\n",
 		},
 		{
 			name:    "bare brackets not touched",
@@ -504,6 +549,11 @@ func TestTransform_StripHTMLComments(t *testing.T) {
 			content: "Hello <!-- hidden --> world",
 			want:    "Hello  world",
 		},
+		{
+			name:    "link in a comment",
+			content: "Hello <!-- [draft](https://example.com) --> world",
+			want:    "Hello  world",
+		},
 	}
 
 	for _, tt := range tests {
@@ -860,6 +910,74 @@ func TestTransform_StripComponentTags(t *testing.T) {
 	}
 }
 
+func TestTransform_ConvertComponents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "DescriptionList items",
+			content: "<DescriptionList>\n\n" +
+				"<DescriptionListItem term=\"VU\">\nA virtual user.\n</DescriptionListItem>\n" +
+				"<DescriptionListItem term=\"Iteration\">\nOne run of the default function.\n" +
+				"It repeats.\n</DescriptionListItem>\n\n</DescriptionList>",
+			want: "\n\n- **VU**: A virtual user.\n" +
+				"- **Iteration**: One run of the default function.\n  It repeats.\n\n",
+		},
+		{
+			name: "Glossary definitions",
+			content: "<Glossary>\n\nConcurrent sessions\n: The number of simultaneous sessions.\n\n" +
+				"Think time\n: Time spent waiting.\n\n</Glossary>",
+			want: "\n\n- **Concurrent sessions**: The number of simultaneous sessions.\n\n" +
+				"- **Think time**: Time spent waiting.\n\n",
+		},
+		{
+			name: "Tabs",
+			content: "<Tabs>\n<Tab label=\"Linux\">\n\n```bash\nsudo apt-get install k6\n```\n\n</Tab>\n" +
+				"<Tab label={\"macOS\"}>\n\n```bash\nbrew install k6\n```\n\n</Tab>\n</Tabs>",
+			want: "\n**Option: Linux**\n\n```bash\nsudo apt-get install k6\n```\n\n" +
+				"**Option: macOS**\n\n```bash\nbrew install k6\n```\n\n",
+		},
+		{
+			name: "Cards",
+			content: "<Cards>\n<Card title=\"Browser\" description=\"Test in a real browser.\" " +
+				"link=\"https://grafana.com/docs/grafana-cloud/testing/k6/\" />\n" +
+				"<Card title='gRPC'>Test gRPC services.</Card>\n</Cards>",
+			want: "\n- **Browser[^1]**: Test in a real browser.\n- **gRPC**: Test gRPC services.\n\n" +
+				"[^1]: https://grafana.com/docs/grafana-cloud/testing/k6/\n",
+		},
+		{
+			name:    "Tab with blank lines in code",
+			content: "<Tab label=\"Linux\">\n\n```bash\na\n\n\n\nb\n```\n\n</Tab>",
+			want:    "**Option: Linux**\n\n```bash\na\n\n\n\nb\n```\n",
+		},
+		{
+			name:    "unknown component still stripped",
+			content: "Before <Callout kind=\"x\">inside</Callout> after",
+			want:    "Before inside after",
+		},
+		{
+			name:    "definition outside a list untouched",
+			content: "Term\n: not a definition list",
+			want:    "Term\n: not a definition list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Transform(tt.content, "v1.0.0")
+			if got != tt.want {
+				t.Errorf("got: %q, want: %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTransform_StripBrTags(t *testing.T) {
 	t.Parallel()
 
@@ -920,3 +1038,12 @@ func TestTransform_NoOp(t *testing.T) {
 		t.Errorf("got: %q, want: %q", got, input)
 	}
 }
+
+func TestConvertComponents_CardLink(t *testing.T) {
+	t.Parallel()
+
+	got := transform(`<Card title="Browser" href="/docs/k6/latest/using-k6-browser/">Test in a browser.</Card>`, "v1.0.0", stageComponents)
+	if want := "- **[Browser](/docs/k6/latest/using-k6-browser/)**: Test in a browser."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffOp is one line of a line diff: ' ' kept, '-' removed, '+' added.
+// a and b are the 0-based line numbers in the old and new text at which
+// the op applies.
+type diffOp struct {
+	kind byte
+	line string
+	a, b int
+}
+
+// diffLines computes a minimal line diff of a and b from their longest
+// common subsequence. Docs sections are at most a few hundred lines, so the
+// quadratic table is cheap.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i], a: i, b: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			// Removals come before additions, as in diff -u.
+			ops = append(ops, diffOp{kind: '-', line: a[i], a: i, b: j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j], a: i, b: j})
+			j++
+		}
+	}
+	return ops
+}
+
+// unifiedDiff returns a unified diff of the lines of a and b with the given
+// file names, or "" if they are equal.
+func unifiedDiff(a, b, fromName, toName string) string {
+	ops := diffLines(splitDiffLines(a), splitDiffLines(b))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for c := 0; c < len(changes); {
+		start := max(changes[c]-diffContext, 0)
+		end := changes[c] + diffContext + 1
+		// Merge changes whose context overlaps into one hunk.
+		for c++; c < len(changes) && changes[c]-diffContext <= end; c++ {
+			end = changes[c] + diffContext + 1
+		}
+		end = min(end, len(ops))
+		writeHunk(&sb, ops[start:end])
+	}
+
+	return sb.String()
+}
+
+// writeHunk writes one hunk header and its lines.
+func writeHunk(sb *strings.Builder, ops []diffOp) {
+	aCount, bCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aCount++
+		}
+		if op.kind != '-' {
+			bCount++
+		}
+	}
+
+	// An empty range starts at the line before it, as in diff -u.
+	aStart, bStart := ops[0].a+1, ops[0].b+1
+	if aCount == 0 {
+		aStart--
+	}
+	if bCount == 0 {
+		bStart--
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, op := range ops {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		sb.WriteByte('\n')
+	}
+}
+
+// splitDiffLines splits s into lines, ignoring a trailing newline.
+func splitDiffLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
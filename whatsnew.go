@@ -0,0 +1,207 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// significantChange is the share of changed lines above which a page is
+// listed as updated by whats-new. Smaller edits such as typo fixes are
+// left out.
+const significantChange = 0.2
+
+// reDocsVersion matches docs versions like v1.5.x.
+var reDocsVersion = regexp.MustCompile(`^v(\d+)\.(\d+)\.x$`)
+
+func newWhatsNewCmd(gs *state.GlobalState, opts *docsOpts) *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "whats-new",
+		Short: "Summarize documentation changes since an older k6 version",
+		Long: "List new modules, new functions and significantly updated pages compared with an older\n" +
+			"docs version. Without --since, the newest older version in the cache is used, or else\n" +
+			"the previous minor version, which is downloaded once.",
+		Example: "  k6 x docs whats-new\n  k6 x docs whats-new --since v1.3.x",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runWhatsNew(gs, cmd, opts, since)
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "Older docs version to compare with (e.g. v1.3.x)")
+
+	return cmd
+}
+
+func runWhatsNew(gs *state.GlobalState, cmd *cobra.Command, opts *docsOpts, since string) error {
+	if err := checkFormat(opts.format); err != nil {
+		return err
+	}
+
+	version, cacheDir, idx, err := setup(gs, opts)
+	if err != nil {
+		return err
+	}
+
+	if since == "" {
+		since, err = previousVersion(gs.FS, gs.Env, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	oldDir, oldIdx, err := loadBundle(gs, opts, since)
+	if err != nil {
+		return err
+	}
+
+	readOld := func(sec *Section) string { return readAndTransform(gs.FS, oldIdx, oldDir, sec, version) }
+	readCur := func(sec *Section) string { return readAndTransform(gs.FS, idx, cacheDir, sec, version) }
+	d := compareIndexes(oldIdx, idx, "", readOld, readCur)
+
+	cfg, cfgErr := loadConfig(gs.FS, gs.Env)
+	if cfgErr != nil {
+		gs.Logger.Warnf("docs: ignoring invalid config: %v", cfgErr)
+	}
+
+	w, render := newOutput(gs, cmd, cfg, opts)
+	printWhatsNew(w, idx, d, version, since)
+	return render()
+}
+
+// parseDocsVersion returns the major and minor number of a docs version.
+func parseDocsVersion(version string) (int, int, bool) {
+	m := reDocsVersion.FindStringSubmatch(version)
+	if m == nil {
+		return 0, 0, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return major, minor, true
+}
+
+// previousVersion picks the version whats-new compares with: the newest
+// cached docs version older than version, or else the previous minor
+// version.
+func previousVersion(afs fsext.Fs, env map[string]string, version string) (string, error) {
+	major, minor, ok := parseDocsVersion(version)
+	if !ok {
+		return "", fmt.Errorf("cannot infer the version before %s; use --since", version)
+	}
+	older := func(maj, mnr int) bool { return maj < major || (maj == major && mnr < minor) }
+
+	best, bestMajor, bestMinor := "", -1, -1
+	if dir, err := CacheDir(env, version); err == nil {
+		entries, _ := fsext.ReadDir(afs, filepath.Dir(dir))
+		for _, e := range entries {
+			maj, mnr, ok := parseDocsVersion(e.Name())
+			if !ok || !e.IsDir() || !older(maj, mnr) {
+				continue
+			}
+			if maj > bestMajor || (maj == bestMajor && mnr > bestMinor) {
+				best, bestMajor, bestMinor = e.Name(), maj, mnr
+			}
+		}
+	}
+	if best != "" {
+		return best, nil
+	}
+
+	if minor == 0 {
+		return "", fmt.Errorf("cannot infer the version before %s; use --since", version)
+	}
+	return fmt.Sprintf("v%d.%d.x", major, minor-1), nil
+}
+
+// changeRatio returns the share of lines that differ between a and b.
+func changeRatio(a, b string) float64 {
+	ops := diffLines(splitDiffLines(a), splitDiffLines(b))
+	if len(ops) == 0 {
+		return 0
+	}
+	changed := 0
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed++
+		}
+	}
+	return float64(changed) / float64(len(ops))
+}
+
+// whatsNewGroup collects the changes within one top-level category.
+type whatsNewGroup struct {
+	modules, functions, pages, updated []listItem
+}
+
+// printWhatsNew writes the whats-new summary of d, grouped by top-level
+// category like printTOC. New sections directly under the JavaScript API are
+// modules and deeper ones are functions.
+func printWhatsNew(w io.Writer, idx *Index, d docsDiff, version, since string) {
+	groups := make(map[string]*whatsNewGroup)
+	group := func(slug string) *whatsNewGroup {
+		cat, _, _ := strings.Cut(slug, "/")
+		if groups[cat] == nil {
+			groups[cat] = &whatsNewGroup{}
+		}
+		return groups[cat]
+	}
+	item := func(sec *Section) listItem {
+		return listItem{Name: commandArgs(idx, sec.Slug), Description: truncate(sec.Description, 80)}
+	}
+
+	for _, sec := range d.added {
+		g := group(sec.Slug)
+		switch depth := strings.Count(sec.Slug, "/"); {
+		case sec.Category == "javascript-api" && depth == 1:
+			g.modules = append(g.modules, item(sec))
+		case sec.Category == "javascript-api" && depth > 1:
+			g.functions = append(g.functions, item(sec))
+		default:
+			g.pages = append(g.pages, item(sec))
+		}
+	}
+	for _, p := range d.changed {
+		if changeRatio(p.fromText, p.toText) >= significantChange {
+			g := group(p.to.Slug)
+			g.updated = append(g.updated, item(p.to))
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "What's new in k6 Documentation (%s, since %s)\n", version, since)
+	if len(groups) == 0 {
+		_, _ = fmt.Fprintln(w, "\nNo new or significantly updated pages.")
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Use: k6 x docs <topic>")
+
+	for _, cat := range idx.TopLevel() {
+		g := groups[cat.Slug]
+		if g == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n## %s\n", cat.Title)
+		for _, part := range []struct {
+			name  string
+			items []listItem
+		}{
+			{"New modules", g.modules},
+			{"New functions", g.functions},
+			{"New pages", g.pages},
+			{"Updated", g.updated},
+		} {
+			if len(part.items) == 0 {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "\n%s:\n", part.name)
+			printAlignedList(w, part.items)
+		}
+	}
+}
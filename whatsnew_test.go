@@ -0,0 +1,104 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestWhatsNew(t *testing.T) {
+	t.Parallel()
+
+	run := setupDiffCaches(t, nil)
+
+	t.Run("since", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "--version", "v0.56.x", "whats-new", "--since", "v0.55.x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGolden(t, "whats-new/since.txt", out)
+	})
+
+	t.Run("infers_cached_version", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "--version", "v0.56.x", "whats-new")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(out, "What's new in k6 Documentation (v0.56.x, since v0.55.x)") {
+			t.Errorf("expected comparison with cached v0.55.x, got:\n%s", out)
+		}
+	})
+
+	t.Run("nothing_new", func(t *testing.T) {
+		t.Parallel()
+		out, err := run(t, "--version", "v0.55.x", "whats-new", "--since", "v0.55.x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "No new or significantly updated pages.") {
+			t.Errorf("expected no changes, got:\n%s", out)
+		}
+	})
+}
+
+func TestWhatsNew_Overlay(t *testing.T) {
+	t.Parallel()
+
+	// Overlays are merged into the current docs only, and are not part of
+	// any docs version, so they are never reported as new pages.
+	run := setupDiffCaches(t, map[string]string{
+		"/home/test/.config/k6/docs.yaml":            "overlays:\n  - dir: ~/company/k6-docs\n",
+		"/home/test/company/k6-docs/auth-helpers.md": "---\ntitle: Auth helpers\n---\n\nUse `login()`.\n",
+	})
+	out, err := run(t, "--version", "v0.55.x", "whats-new", "--since", "v0.55.x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "No new or significantly updated pages.") {
+		t.Errorf("expected no changes, got:\n%s", out)
+	}
+	if out, _ := run(t, "--version", "v0.55.x", "internal", "auth-helpers"); !strings.Contains(out, "Use `login()`.") {
+		t.Errorf("expected the overlay to be merged, got:\n%s", out)
+	}
+}
+
+func TestPreviousVersion(t *testing.T) {
+	t.Parallel()
+
+	afs := fsext.NewMemMapFs()
+	env := map[string]string{"HOME": "/home/test"}
+	for _, v := range []string{"v1.2.x", "v1.4.x", "v1.6.x", "latest"} {
+		if err := afs.MkdirAll("/home/test/.local/share/k6/docs/"+v, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "v1.5.x", want: "v1.4.x"},
+		{version: "v1.4.x", want: "v1.2.x"},
+		{version: "v1.2.x", want: "v1.1.x"},
+		{version: "v2.0.x", want: "v1.6.x"},
+		{version: "v1.0.x", wantErr: true},
+		{version: "dev", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := previousVersion(afs, env, tt.version)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("previousVersion(%q): expected error, got %q", tt.version, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("previousVersion(%q) = %q, %v; want %q", tt.version, got, err, tt.want)
+		}
+	}
+}